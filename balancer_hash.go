@@ -0,0 +1,159 @@
+package fastshot
+
+import (
+	"cmp"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/opus-domini/fast-shot/constant/header"
+)
+
+// Compile-time check that ConsistentHashStrategy implements LoadBalancingStrategy.
+var _ LoadBalancingStrategy = (*ConsistentHashStrategy)(nil)
+
+// defaultHashPointsPerWeight is the number of ring points a backend gets per unit of weight.
+const defaultHashPointsPerWeight = 100
+
+type (
+	// HashKeyFunc derives the consistent hashing key of a request. It returns an empty string
+	// when the request has no key.
+	HashKeyFunc func(req *http.Request) string
+
+	// ConsistentHashStrategy pins requests with the same hash key to the same backend using a
+	// ring hash. Each backend owns points on the ring in proportion to its weight, and a key
+	// belongs to the backend of the first point at or after its hash. When backends are added or
+	// removed, only the keys of the points they gain or lose move. Requests without a key are
+	// spread in round-robin order.
+	ConsistentHashStrategy struct {
+		mu     sync.Mutex
+		ring   []hashRingPoint
+		hosts  map[string]int
+		next   atomic.Uint32
+		points float64
+	}
+
+	// hashRingPoint is a point of the ring owned by a backend.
+	hashRingPoint struct {
+		hash    uint64
+		baseURL string
+	}
+)
+
+// HashKeyFromHeader derives the hash key from the value of a request header.
+func HashKeyFromHeader(key header.Type) HashKeyFunc {
+	return func(req *http.Request) string {
+		return req.Header.Get(string(key))
+	}
+}
+
+// HashKeyFromQuery derives the hash key from the value of a query parameter.
+func HashKeyFromQuery(param string) HashKeyFunc {
+	return func(req *http.Request) string {
+		return req.URL.Query().Get(param)
+	}
+}
+
+// HashKeyFromCookie derives the hash key from the value of a cookie.
+func HashKeyFromCookie(name string) HashKeyFunc {
+	return func(req *http.Request) string {
+		cookie, err := req.Cookie(name)
+		if err != nil {
+			return ""
+		}
+		return cookie.Value
+	}
+}
+
+// NewConsistentHashStrategy creates a ring hash strategy with 100 ring points per unit of weight.
+func NewConsistentHashStrategy() *ConsistentHashStrategy {
+	return &ConsistentHashStrategy{points: defaultHashPointsPerWeight}
+}
+
+// Select for ConsistentHashStrategy returns the backend that owns the hash key of the request.
+func (s *ConsistentHashStrategy) Select(backends []Backend, criteria BaseURLCriteria) int {
+	if criteria.HashKey == "" {
+		return int((s.next.Add(1) - 1) % uint32(len(backends)))
+	}
+
+	indexes := make(map[string]int, len(backends))
+	for index, backend := range backends {
+		indexes[backend.URL.String()] = index
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Points only depend on the base URL, so backends left out of this selection, e.g. excluded
+	// ones, can keep their points and be skipped below. The ring only has to be rebuilt when a
+	// backend is new or its number of points changed.
+	for _, backend := range backends {
+		if count, ok := s.hosts[backend.URL.String()]; !ok || count != s.pointCount(backend.Weight) {
+			s.rebuild(backends)
+			break
+		}
+	}
+
+	hash := hashKey(criteria.HashKey)
+	start, _ := slices.BinarySearchFunc(s.ring, hash, func(point hashRingPoint, target uint64) int {
+		return cmp.Compare(point.hash, target)
+	})
+	for offset := range len(s.ring) {
+		point := s.ring[(start+offset)%len(s.ring)]
+		if index, ok := indexes[point.baseURL]; ok {
+			return index
+		}
+	}
+	return 0
+}
+
+// Start for ConsistentHashStrategy does nothing.
+func (s *ConsistentHashStrategy) Start(*url.URL) {}
+
+// Finish for ConsistentHashStrategy does nothing.
+func (s *ConsistentHashStrategy) Finish(*url.URL) {}
+
+// pointCount returns the number of ring points of a backend with the given weight.
+func (s *ConsistentHashStrategy) pointCount(weight float64) int {
+	return max(int(math.Round(weight*s.points)), 1)
+}
+
+// rebuild places the points of the given backends on the ring. The caller must hold the lock.
+func (s *ConsistentHashStrategy) rebuild(backends []Backend) {
+	s.hosts = make(map[string]int, len(backends))
+	for _, backend := range backends {
+		s.hosts[backend.URL.String()] = s.pointCount(backend.Weight)
+	}
+
+	s.ring = s.ring[:0]
+	for baseURL, count := range s.hosts {
+		for replica := range count {
+			s.ring = append(s.ring, hashRingPoint{
+				hash:    hashKey(baseURL + "#" + strconv.Itoa(replica)),
+				baseURL: baseURL,
+			})
+		}
+	}
+	slices.SortFunc(s.ring, func(a, b hashRingPoint) int {
+		return cmp.Compare(a.hash, b.hash)
+	})
+}
+
+// hashKey hashes a key onto the ring with FNV-1a, finalized with the SplitMix64 mixer to spread
+// similar keys across the whole ring.
+func hashKey(key string) uint64 {
+	hasher := fnv.New64a()
+	_, _ = hasher.Write([]byte(key))
+	hash := hasher.Sum64()
+	hash ^= hash >> 30
+	hash *= 0xbf58476d1ce4e5b9
+	hash ^= hash >> 27
+	hash *= 0x94d049bb133111eb
+	hash ^= hash >> 31
+	return hash
+}
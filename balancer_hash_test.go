@@ -0,0 +1,145 @@
+package fastshot
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func TestHashKeyFunc(t *testing.T) {
+	// Arrange
+	req, _ := http.NewRequest(http.MethodGet, "/users?tenant=acme", nil)
+	req.Header.Set("X-Tenant-ID", "globex")
+	req.AddCookie(&http.Cookie{Name: "session", Value: "abc123"})
+
+	tests := []struct {
+		name     string
+		hashKey  HashKeyFunc
+		expected string
+	}{
+		{name: "From header", hashKey: HashKeyFromHeader("X-Tenant-ID"), expected: "globex"},
+		{name: "From missing header", hashKey: HashKeyFromHeader("X-Missing"), expected: ""},
+		{name: "From query", hashKey: HashKeyFromQuery("tenant"), expected: "acme"},
+		{name: "From missing query", hashKey: HashKeyFromQuery("missing"), expected: ""},
+		{name: "From cookie", hashKey: HashKeyFromCookie("session"), expected: "abc123"},
+		{name: "From missing cookie", hashKey: HashKeyFromCookie("missing"), expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := tt.hashKey(req)
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConsistentHashStrategy_SameKey(t *testing.T) {
+	// Arrange
+	backends := newTestBackends(1, 1, 1, 1)
+	strategy := NewConsistentHashStrategy()
+	criteria := BaseURLCriteria{HashKey: "tenant-42"}
+	want := strategy.Select(backends, criteria)
+
+	// Act & Assert
+	for i := range 10 {
+		if got := strategy.Select(backends, criteria); got != want {
+			t.Errorf("selection %d got %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestConsistentHashStrategy_NoKey(t *testing.T) {
+	// Arrange
+	backends := newTestBackends(1, 1, 1)
+	strategy := NewConsistentHashStrategy()
+
+	// Act & Assert
+	for i, want := range []int{0, 1, 2, 0} {
+		if got := strategy.Select(backends, BaseURLCriteria{}); got != want {
+			t.Errorf("selection %d got %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestConsistentHashStrategy_MembershipChanges(t *testing.T) {
+	backends := newTestBackends(1, 1, 1, 1, 1)
+
+	tests := []struct {
+		name   string
+		before []Backend
+		after  []Backend
+	}{
+		{
+			name:   "Removing a backend only moves its keys",
+			before: backends,
+			after:  append(append([]Backend{}, backends[:2]...), backends[3:]...),
+		},
+		{
+			name:   "Adding a backend only moves keys to it",
+			before: backends[:4],
+			after:  backends,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			strategy := NewConsistentHashStrategy()
+			owners := make(map[string]*url.URL)
+			for i := range 1000 {
+				key := "key-" + strconv.Itoa(i)
+				owners[key] = tt.before[strategy.Select(tt.before, BaseURLCriteria{HashKey: key})].URL
+			}
+
+			// Act
+			moved := 0
+			for key, before := range owners {
+				after := tt.after[strategy.Select(tt.after, BaseURLCriteria{HashKey: key})].URL
+				if after == before {
+					continue
+				}
+				moved++
+
+				// Assert - a key only moves away from a removed backend or to an added one
+				if containsBackend(tt.after, before) && containsBackend(tt.before, after) {
+					t.Errorf("key %s moved from %v to %v, both present before and after", key, before, after)
+				}
+			}
+			if moved == 0 || moved > 400 {
+				t.Errorf("moved keys got %d, want between 1 and 400", moved)
+			}
+		})
+	}
+}
+
+func TestConsistentHashStrategy_Weights(t *testing.T) {
+	// Arrange
+	backends := newTestBackends(3, 1)
+	strategy := NewConsistentHashStrategy()
+
+	// Act
+	counts := make([]int, len(backends))
+	for i := range 4000 {
+		counts[strategy.Select(backends, BaseURLCriteria{HashKey: "key-" + strconv.Itoa(i)})]++
+	}
+
+	// Assert
+	if share := float64(counts[0]) / 4000; share < 0.65 || share > 0.85 {
+		t.Errorf("heavier backend share got %.2f, want about 0.75", share)
+	}
+}
+
+func containsBackend(backends []Backend, baseURL *url.URL) bool {
+	for _, backend := range backends {
+		if backend.URL == baseURL {
+			return true
+		}
+	}
+	return false
+}
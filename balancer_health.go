@@ -0,0 +1,202 @@
+package fastshot
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Default values of HealthCheckConfig.
+const (
+	defaultHealthCheckPath               = "/"
+	defaultHealthCheckInterval           = 10 * time.Second
+	defaultHealthCheckTimeout            = 2 * time.Second
+	defaultHealthCheckHealthyThreshold   = 2
+	defaultHealthCheckUnhealthyThreshold = 3
+)
+
+type (
+	// HealthCheckConfig configures the active health checking of load-balanced base URLs.
+	// Zero values fall back to the defaults documented on each field.
+	HealthCheckConfig struct {
+		// Path is probed on every base URL. Defaults to "/".
+		Path string
+		// Interval is the time between probes. Defaults to 10 seconds.
+		Interval time.Duration
+		// Timeout bounds each probe. Defaults to 2 seconds.
+		Timeout time.Duration
+		// ExpectedStatus is the status code of a healthy response. Defaults to any 2xx status.
+		ExpectedStatus int
+		// HealthyThreshold is the number of consecutive successful probes that puts an unhealthy
+		// base URL back in rotation. Defaults to 2.
+		HealthyThreshold uint
+		// UnhealthyThreshold is the number of consecutive failed probes that takes a base URL
+		// out of rotation. Defaults to 3.
+		UnhealthyThreshold uint
+	}
+
+	// HealthChecker probes load-balanced base URLs in the background and takes the unhealthy ones
+	// out of rotation until they recover. Base URLs start healthy. Close stops the probes.
+	HealthChecker struct {
+		config HealthCheckConfig
+		ctx    context.Context
+		cancel context.CancelFunc
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+	}
+
+	// healthCounter counts the consecutive probe results of a base URL.
+	healthCounter struct {
+		successes uint
+		failures  uint
+	}
+)
+
+// NewHealthChecker creates a HealthChecker with the given configuration. It starts probing once
+// attached to a client with LoadBalancer().WithHealthCheck.
+func NewHealthChecker(config HealthCheckConfig) *HealthChecker {
+	if config.Path == "" {
+		config.Path = defaultHealthCheckPath
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultHealthCheckInterval
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultHealthCheckTimeout
+	}
+	if config.HealthyThreshold == 0 {
+		config.HealthyThreshold = defaultHealthCheckHealthyThreshold
+	}
+	if config.UnhealthyThreshold == 0 {
+		config.UnhealthyThreshold = defaultHealthCheckUnhealthyThreshold
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &HealthChecker{
+		config: config,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Config for HealthChecker returns the effective configuration.
+func (h *HealthChecker) Config() HealthCheckConfig {
+	return h.config
+}
+
+// Close for HealthChecker stops the probes, cancelling the ones in flight, and waits for them to
+// return. It is safe to call more than once.
+func (h *HealthChecker) Close() error {
+	h.mu.Lock()
+	h.cancel()
+	h.mu.Unlock()
+
+	h.wg.Wait()
+	return nil
+}
+
+// start probes the balancer's base URLs every interval until the checker is closed, using the
+// HTTP client returned by httpClient at the time of each probe.
+func (h *HealthChecker) start(balancer *BalancedBaseURL, httpClient func() HttpClientComponent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.ctx.Err() != nil {
+		return
+	}
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+
+		counters := make(map[*url.URL]*healthCounter)
+		ticker := time.NewTicker(h.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			h.probeAll(balancer, httpClient(), counters)
+			select {
+			case <-h.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// probeAll probes every base URL concurrently and updates their health.
+func (h *HealthChecker) probeAll(balancer *BalancedBaseURL, client HttpClientComponent, counters map[*url.URL]*healthCounter) {
+	backends := balancer.Backends()
+	results := make([]bool, len(backends))
+
+	var wg sync.WaitGroup
+	for index, backend := range backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[index] = h.probe(client, backend.URL)
+		}()
+	}
+	wg.Wait()
+
+	// Probes cut short by Close say nothing about the base URLs
+	if h.ctx.Err() != nil {
+		return
+	}
+
+	// Forget the base URLs that are no longer members
+	for baseURL := range counters {
+		if !slices.ContainsFunc(backends, func(backend Backend) bool { return backend.URL == baseURL }) {
+			delete(counters, baseURL)
+		}
+	}
+
+	for index, backend := range backends {
+		counter, ok := counters[backend.URL]
+		if !ok {
+			counter = &healthCounter{}
+			counters[backend.URL] = counter
+		}
+
+		if results[index] {
+			counter.successes++
+			counter.failures = 0
+			if counter.successes >= h.config.HealthyThreshold && !balancer.IsHealthy(backend.URL) {
+				balancer.SetHealthy(backend.URL, true)
+			}
+			continue
+		}
+
+		counter.failures++
+		counter.successes = 0
+		if counter.failures >= h.config.UnhealthyThreshold && balancer.IsHealthy(backend.URL) {
+			balancer.SetHealthy(backend.URL, false)
+		}
+	}
+}
+
+// probe sends a GET request to the health check path of the base URL and reports whether it
+// answered with the expected status.
+func (h *HealthChecker) probe(client HttpClientComponent, baseURL *url.URL) bool {
+	ctx, cancel := context.WithTimeout(h.ctx, h.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL.JoinPath(h.config.Path).String(), nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDiscardBytes))
+
+	if h.config.ExpectedStatus != 0 {
+		return resp.StatusCode == h.config.ExpectedStatus
+	}
+	return resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices
+}
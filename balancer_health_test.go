@@ -0,0 +1,230 @@
+package fastshot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewHealthChecker(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   HealthCheckConfig
+		expected HealthCheckConfig
+	}{
+		{
+			name:   "Defaults",
+			config: HealthCheckConfig{},
+			expected: HealthCheckConfig{
+				Path:               "/",
+				Interval:           10 * time.Second,
+				Timeout:            2 * time.Second,
+				HealthyThreshold:   2,
+				UnhealthyThreshold: 3,
+			},
+		},
+		{
+			name: "Custom values",
+			config: HealthCheckConfig{
+				Path:               "/health",
+				Interval:           time.Second,
+				Timeout:            100 * time.Millisecond,
+				ExpectedStatus:     http.StatusNoContent,
+				HealthyThreshold:   1,
+				UnhealthyThreshold: 1,
+			},
+			expected: HealthCheckConfig{
+				Path:               "/health",
+				Interval:           time.Second,
+				Timeout:            100 * time.Millisecond,
+				ExpectedStatus:     http.StatusNoContent,
+				HealthyThreshold:   1,
+				UnhealthyThreshold: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			checker := NewHealthChecker(tt.config)
+			defer func() { _ = checker.Close() }()
+
+			// Assert
+			if got := checker.Config(); got != tt.expected {
+				t.Errorf("got %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHealthChecker_Thresholds(t *testing.T) {
+	// Arrange
+	var healthy atomic.Bool
+	var lastPath atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastPath.Store(r.URL.Path)
+		if healthy.Load() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	balancer := newBalancedBaseURL([]*url.URL{u})
+	checker := NewHealthChecker(HealthCheckConfig{Path: "/health", HealthyThreshold: 2, UnhealthyThreshold: 2})
+	defer func() { _ = checker.Close() }()
+	counters := make(map[*url.URL]*healthCounter)
+
+	steps := []struct {
+		healthy  bool
+		expected bool
+	}{
+		{healthy: false, expected: true},
+		{healthy: false, expected: false},
+		{healthy: true, expected: false},
+		{healthy: false, expected: false},
+		{healthy: true, expected: false},
+		{healthy: true, expected: true},
+	}
+
+	for i, step := range steps {
+		// Act
+		healthy.Store(step.healthy)
+		checker.probeAll(balancer, newDefaultHttpClient(), counters)
+
+		// Assert
+		if got := balancer.IsHealthy(u); got != step.expected {
+			t.Errorf("step %d healthy got %v, want %v", i, got, step.expected)
+		}
+	}
+	if got := lastPath.Load(); got != "/health" {
+		t.Errorf("probe path got %v, want /health", got)
+	}
+}
+
+func TestHealthChecker_Probe(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		expectedStatus int
+		expected       bool
+	}{
+		{name: "Any 2xx by default", status: http.StatusNoContent, expected: true},
+		{name: "Non 2xx by default", status: http.StatusInternalServerError, expected: false},
+		{name: "Expected status matches", status: http.StatusTeapot, expectedStatus: http.StatusTeapot, expected: true},
+		{name: "Expected status differs", status: http.StatusOK, expectedStatus: http.StatusNoContent, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			u, _ := url.Parse(server.URL)
+			checker := NewHealthChecker(HealthCheckConfig{ExpectedStatus: tt.expectedStatus})
+			defer func() { _ = checker.Close() }()
+
+			// Act
+			got := checker.probe(newDefaultHttpClient(), u)
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHealthChecker_ProbeTimeout(t *testing.T) {
+	// Arrange
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	u, _ := url.Parse(server.URL)
+	checker := NewHealthChecker(HealthCheckConfig{Timeout: 20 * time.Millisecond})
+	defer func() { _ = checker.Close() }()
+
+	// Act
+	got := checker.probe(newDefaultHttpClient(), u)
+
+	// Assert
+	if got {
+		t.Error("got healthy, want unhealthy after timeout")
+	}
+}
+
+func TestHealthChecker_Close(t *testing.T) {
+	// Arrange
+	var probes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	balancer := newBalancedBaseURL([]*url.URL{u})
+	checker := NewHealthChecker(HealthCheckConfig{Interval: 5 * time.Millisecond})
+	client := newDefaultHttpClient()
+	checker.start(balancer, func() HttpClientComponent { return client })
+
+	deadline := time.Now().Add(time.Second)
+	for probes.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Act
+	if err := checker.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checker.Close(); err != nil {
+		t.Fatalf("unexpected error on second close: %v", err)
+	}
+	stopped := probes.Load()
+	checker.start(balancer, func() HttpClientComponent { return client })
+	time.Sleep(20 * time.Millisecond)
+
+	// Assert
+	if stopped < 2 {
+		t.Errorf("probes before close got %d, want at least 2", stopped)
+	}
+	if got := probes.Load(); got != stopped {
+		t.Errorf("probes after close got %d, want %d", got, stopped)
+	}
+}
+
+func TestHealthChecker_InvalidBaseURL(t *testing.T) {
+	// Arrange
+	checker := NewHealthChecker(HealthCheckConfig{Interval: time.Hour, Timeout: 10 * time.Millisecond})
+	builder := NewClientLoadBalancer([]string{"http://a.invalid", "://bad"})
+
+	// Act
+	builder.LoadBalancer().WithHealthCheck(checker).Build()
+	_ = checker.Close()
+
+	// Assert
+	if builder.client.Validations().Count() == 0 {
+		t.Error("got no validation error, want the invalid base URL reported")
+	}
+	for _, backend := range builder.client.(*ClientConfigBase).ConfigBaseURL.(*BalancedBaseURL).Backends() {
+		if backend.URL == nil {
+			t.Error("got a backend without URL, want invalid base URLs left out")
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package fastshot
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"sync"
+)
+
+// Compile-time checks that BalancedBaseURL implements Membership and EndpointSet implements Endpoints.
+var (
+	_ Membership = (*BalancedBaseURL)(nil)
+	_ Endpoints  = (*EndpointSet)(nil)
+)
+
+// EndpointSet is an Endpoints source updated by hand, e.g. from a service registry sync. Every
+// client watching the set follows its updates without being rebuilt.
+type EndpointSet struct {
+	mu        sync.Mutex
+	endpoints []Endpoint
+	members   []Membership
+}
+
+// NewEndpointSet creates an EndpointSet with the given initial endpoints.
+func NewEndpointSet(endpoints []Endpoint) *EndpointSet {
+	return &EndpointSet{endpoints: slices.Clone(endpoints)}
+}
+
+// Endpoints for EndpointSet returns the current endpoints.
+func (s *EndpointSet) Endpoints() []Endpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.endpoints)
+}
+
+// Watch for EndpointSet applies the current endpoints to the membership, and every update after that.
+func (s *EndpointSet) Watch(membership Membership) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.members = append(s.members, membership)
+	if len(s.endpoints) > 0 {
+		_ = membership.SetEndpoints(slices.Clone(s.endpoints))
+	}
+}
+
+// Replace for EndpointSet replaces every endpoint. Invalid or empty updates are rejected and the
+// current endpoints are kept.
+func (s *EndpointSet) Replace(endpoints []Endpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.apply(slices.Clone(endpoints))
+}
+
+// Add for EndpointSet adds endpoints to the set. Endpoints whose base URL is already in the set
+// replace the current ones instead, so their weight, zone and priority are updated while the
+// backend keeps its state.
+func (s *EndpointSet) Add(endpoints ...Endpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated := slices.Clone(s.endpoints)
+	for _, endpoint := range endpoints {
+		index := slices.IndexFunc(updated, func(current Endpoint) bool {
+			return current.BaseURL == endpoint.BaseURL
+		})
+		if index >= 0 {
+			updated[index] = endpoint
+			continue
+		}
+		updated = append(updated, endpoint)
+	}
+	return s.apply(updated)
+}
+
+// Drain for EndpointSet takes the base URL out of rotation right away, then waits until the
+// requests already sent to it finish or the context is done.
+func (s *EndpointSet) Drain(ctx context.Context, baseURL string) error {
+	s.mu.Lock()
+	updated := slices.DeleteFunc(slices.Clone(s.endpoints), func(endpoint Endpoint) bool {
+		return endpoint.BaseURL == baseURL
+	})
+	err := s.apply(updated)
+	members := slices.Clone(s.members)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, membership := range members {
+		if err := membership.WaitDrained(ctx, baseURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply validates the endpoints and pushes them to every membership. The caller must hold the lock.
+func (s *EndpointSet) apply(endpoints []Endpoint) error {
+	if _, err := parseEndpoints(endpoints); err != nil {
+		return err
+	}
+	for _, membership := range s.members {
+		if err := membership.SetEndpoints(slices.Clone(endpoints)); err != nil {
+			return err
+		}
+	}
+	s.endpoints = endpoints
+	return nil
+}
+
+// SetEndpoints for BalancedBaseURL replaces the backends. Base URLs that stay keep their state,
+// such as health and in-flight requests, while removed ones leave the rotation right away and
+// their requests in flight finish normally. Repeated base URLs are listed once. Invalid or empty
+// updates are rejected and the current backends are kept.
+func (c *BalancedBaseURL) SetEndpoints(endpoints []Endpoint) error {
+	backends, err := parseEndpoints(endpoints)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	previous := c.backends
+	for index, backend := range backends {
+		// Reuse the URL of a known base URL so every per-backend state follows it
+		if known := slices.IndexFunc(previous, func(current Backend) bool {
+			return current.URL.String() == backend.URL.String()
+		}); known >= 0 {
+			backends[index].URL = previous[known].URL
+			previous = slices.Delete(slices.Clone(previous), known, known+1)
+		} else if len(c.backends) > 0 {
+			c.markJoined(backend.URL)
+		}
+	}
+	c.backends = backends
+	for _, removed := range previous {
+		delete(c.unhealthy, removed.URL)
+		delete(c.joined, removed.URL)
+	}
+	c.mu.Unlock()
+
+	if c.outliers != nil {
+		for _, removed := range previous {
+			c.outliers.forget(removed.URL)
+		}
+	}
+	if forgetter, ok := c.strategy.(backendForgetter); ok {
+		for _, removed := range previous {
+			forgetter.forget(removed.URL)
+		}
+	}
+	return nil
+}
+
+// WaitDrained for BalancedBaseURL waits until no request to the base URL is in flight, or the
+// context is done.
+func (c *BalancedBaseURL) WaitDrained(ctx context.Context, baseURL string) error {
+	key := baseURL
+	if parsedURL, err := url.Parse(baseURL); err == nil {
+		key = parsedURL.String()
+	}
+
+	for {
+		// A base URL that left and joined again may have requests in flight under both of its URLs
+		c.mu.Lock()
+		inflight := c.inflightURL(key)
+		if inflight == nil {
+			c.mu.Unlock()
+			return nil
+		}
+		if c.drained == nil {
+			c.drained = make(map[*url.URL][]chan struct{})
+		}
+		done := make(chan struct{})
+		c.drained[inflight] = append(c.drained[inflight], done)
+		c.mu.Unlock()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// inflightURL returns a URL of the base URL with requests in flight, or nil if there is none.
+// The caller must hold the lock.
+func (c *BalancedBaseURL) inflightURL(baseURL string) *url.URL {
+	for inflight := range c.inflight {
+		if inflight.String() == baseURL {
+			return inflight
+		}
+	}
+	return nil
+}
@@ -0,0 +1,396 @@
+package fastshot
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBalancedBaseURL_SetEndpoints(t *testing.T) {
+	tests := []struct {
+		name        string
+		endpoints   []Endpoint
+		expectError bool
+		expected    []string
+	}{
+		{
+			name:      "Replace backends",
+			endpoints: []Endpoint{{BaseURL: "https://b.com"}, {BaseURL: "https://c.com", Weight: 3}},
+			expected:  []string{"https://b.com", "https://c.com"},
+		},
+		{
+			name:      "Duplicate base URLs are listed once",
+			endpoints: []Endpoint{{BaseURL: "https://b.com"}, {BaseURL: "https://c.com"}, {BaseURL: "https://b.com", Weight: 3}},
+			expected:  []string{"https://b.com", "https://c.com"},
+		},
+		{
+			name:        "Empty update is rejected",
+			endpoints:   []Endpoint{},
+			expectError: true,
+			expected:    []string{"https://a.com", "https://b.com"},
+		},
+		{
+			name:        "Invalid update is rejected",
+			endpoints:   []Endpoint{{BaseURL: "https://c.com"}, {BaseURL: ":%^:"}},
+			expectError: true,
+			expected:    []string{"https://a.com", "https://b.com"},
+		},
+		{
+			name:        "Update with relative base URL is rejected",
+			endpoints:   []Endpoint{{BaseURL: "https://c.com"}, {BaseURL: "/api"}},
+			expectError: true,
+			expected:    []string{"https://a.com", "https://b.com"},
+		},
+		{
+			name:        "Update without host is rejected",
+			endpoints:   []Endpoint{{BaseURL: "https://c.com"}, {BaseURL: "https://"}},
+			expectError: true,
+			expected:    []string{"https://a.com", "https://b.com"},
+		},
+		{
+			name:        "Update with empty base URL is rejected",
+			endpoints:   []Endpoint{{BaseURL: "https://c.com"}, {BaseURL: ""}},
+			expectError: true,
+			expected:    []string{"https://a.com", "https://b.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			u1, _ := url.Parse("https://a.com")
+			u2, _ := url.Parse("https://b.com")
+			base := newBalancedBaseURL([]*url.URL{u1, u2})
+
+			// Act
+			err := base.SetEndpoints(tt.endpoints)
+
+			// Assert
+			if (err != nil) != tt.expectError {
+				t.Fatalf("error got %v, want error %v", err, tt.expectError)
+			}
+			var got []string
+			for _, backend := range base.Backends() {
+				got = append(got, backend.URL.String())
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBalancedBaseURL_SetEndpointsKeepsState(t *testing.T) {
+	// Arrange
+	u1, _ := url.Parse("https://a.com")
+	u2, _ := url.Parse("https://b.com")
+	base := newBalancedBaseURL([]*url.URL{u1, u2})
+	base.SetHealthy(u1, false)
+	base.SetHealthy(u2, false)
+
+	// Act
+	err := base.SetEndpoints([]Endpoint{{BaseURL: "https://a.com", Weight: 2}, {BaseURL: "https://c.com"}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	backends := base.Backends()
+	if backends[0].URL != u1 {
+		t.Errorf("kept base URL got %p, want the same URL %p", backends[0].URL, u1)
+	}
+	if backends[0].Weight != 2 {
+		t.Errorf("kept base URL weight got %v, want 2", backends[0].Weight)
+	}
+	if base.IsHealthy(u1) {
+		t.Error("kept base URL got healthy, want its unhealthy state kept")
+	}
+	if _, ok := base.unhealthy[u2]; ok {
+		t.Error("removed base URL health state was not cleaned up")
+	}
+	for i := range 4 {
+		if got := base.BaseURL(); got != backends[1].URL {
+			t.Errorf("selection %d got %v, want %v", i, got, backends[1].URL)
+		}
+	}
+}
+
+func TestBalancedBaseURL_SetEndpointsForgetsStrategyState(t *testing.T) {
+	// Arrange
+	u1, _ := url.Parse("https://a.com")
+	u2, _ := url.Parse("https://b.com")
+	base := newWeightedBalancedBaseURL([]*url.URL{u1, u2}, []uint{3, 1})
+	base.BaseURL()
+	strategy := base.Strategy().(*WeightedRoundRobinStrategy)
+
+	// Act
+	err := base.SetEndpoints([]Endpoint{{BaseURL: "https://b.com", Weight: 1}, {BaseURL: "https://c.com", Weight: 2}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := strategy.current[u1]; ok {
+		t.Error("removed base URL weight was not forgotten")
+	}
+	if _, ok := strategy.current[u2]; !ok {
+		t.Error("kept base URL weight was forgotten")
+	}
+}
+
+func TestBalancedBaseURL_WaitDrained(t *testing.T) {
+	tests := []struct {
+		name        string
+		inflight    int
+		finishAfter time.Duration
+		timeout     time.Duration
+		expectedErr error
+	}{
+		{
+			name:    "Nothing in flight",
+			timeout: time.Second,
+		},
+		{
+			name:        "Requests in flight finish",
+			inflight:    2,
+			finishAfter: 10 * time.Millisecond,
+			timeout:     time.Second,
+		},
+		{
+			name:        "Context done first",
+			inflight:    1,
+			finishAfter: time.Second,
+			timeout:     10 * time.Millisecond,
+			expectedErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			u1, _ := url.Parse("https://a.com")
+			u2, _ := url.Parse("https://b.com")
+			base := newBalancedBaseURL([]*url.URL{u1, u2})
+
+			var finishers []func()
+			for range tt.inflight {
+				finishers = append(finishers, base.RequestStarted(u1))
+			}
+			_ = base.SetEndpoints([]Endpoint{{BaseURL: "https://b.com"}})
+
+			timer := time.AfterFunc(tt.finishAfter, func() {
+				for _, finished := range finishers {
+					finished()
+				}
+			})
+			defer timer.Stop()
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			// Act
+			err := base.WaitDrained(ctx, "https://a.com")
+
+			// Assert
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("got %v, want %v", err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestBalancedBaseURL_WaitDrainedRejoined(t *testing.T) {
+	// Arrange
+	u1, _ := url.Parse("https://a.com")
+	u2, _ := url.Parse("https://b.com")
+	base := newBalancedBaseURL([]*url.URL{u1, u2})
+	finishedBefore := base.RequestStarted(u1)
+	_ = base.SetEndpoints([]Endpoint{{BaseURL: "https://b.com"}})
+	_ = base.SetEndpoints([]Endpoint{{BaseURL: "https://a.com"}, {BaseURL: "https://b.com"}})
+	rejoined := base.Backends()[0].URL
+	finishedAfter := base.RequestStarted(rejoined)
+
+	drained := make(chan error, 1)
+	go func() {
+		drained <- base.WaitDrained(context.Background(), "https://a.com")
+	}()
+
+	// Act
+	finishedBefore()
+
+	// Assert
+	select {
+	case err := <-drained:
+		t.Fatalf("got drained with %v, want to wait for the request to the rejoined base URL", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	finishedAfter()
+	select {
+	case err := <-drained:
+		if err != nil {
+			t.Errorf("got %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("got still waiting, want drained")
+	}
+}
+
+func TestBalancedBaseURL_ConcurrentMembership(t *testing.T) {
+	// Arrange
+	u1, _ := url.Parse("https://a.com")
+	base := newBalancedBaseURL([]*url.URL{u1})
+	updates := [][]Endpoint{
+		{{BaseURL: "https://a.com"}, {BaseURL: "https://b.com"}},
+		{{BaseURL: "https://c.com", Weight: 2}},
+		{{BaseURL: "https://a.com"}},
+	}
+
+	// Act
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = base.SetEndpoints(updates[i%len(updates)])
+		}()
+		go func() {
+			defer wg.Done()
+			baseURL := base.BaseURL()
+			base.RequestStarted(baseURL)()
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	if got := base.BaseURL(); got == nil {
+		t.Error("got nil base URL, want a member")
+	}
+}
+
+func TestEndpointSet(t *testing.T) {
+	initial := []Endpoint{{BaseURL: "https://a.com"}, {BaseURL: "https://b.com"}}
+
+	tests := []struct {
+		name        string
+		update      func(*EndpointSet) error
+		expectError bool
+		expected    []Endpoint
+	}{
+		{
+			name: "Replace endpoints",
+			update: func(s *EndpointSet) error {
+				return s.Replace([]Endpoint{{BaseURL: "https://c.com"}})
+			},
+			expected: []Endpoint{{BaseURL: "https://c.com"}},
+		},
+		{
+			name: "Invalid replace keeps endpoints",
+			update: func(s *EndpointSet) error {
+				return s.Replace([]Endpoint{{BaseURL: ":%^:"}})
+			},
+			expectError: true,
+			expected:    initial,
+		},
+		{
+			name: "Add endpoints",
+			update: func(s *EndpointSet) error {
+				return s.Add(Endpoint{BaseURL: "https://c.com", Weight: 2})
+			},
+			expected: []Endpoint{{BaseURL: "https://a.com"}, {BaseURL: "https://b.com"}, {BaseURL: "https://c.com", Weight: 2}},
+		},
+		{
+			name: "Add known endpoint updates its weight",
+			update: func(s *EndpointSet) error {
+				return s.Add(Endpoint{BaseURL: "https://b.com", Weight: 5})
+			},
+			expected: []Endpoint{{BaseURL: "https://a.com"}, {BaseURL: "https://b.com", Weight: 5}},
+		},
+		{
+			name: "Add known endpoint replaces it",
+			update: func(s *EndpointSet) error {
+				return s.Add(Endpoint{BaseURL: "https://a.com", Weight: 3, Zone: "us-east-1b", Priority: 1})
+			},
+			expected: []Endpoint{{BaseURL: "https://a.com", Weight: 3, Zone: "us-east-1b", Priority: 1}, {BaseURL: "https://b.com"}},
+		},
+		{
+			name: "Drain endpoint",
+			update: func(s *EndpointSet) error {
+				return s.Drain(context.Background(), "https://a.com")
+			},
+			expected: []Endpoint{{BaseURL: "https://b.com"}},
+		},
+		{
+			name: "Draining the last endpoint is rejected",
+			update: func(s *EndpointSet) error {
+				_ = s.Drain(context.Background(), "https://a.com")
+				return s.Drain(context.Background(), "https://b.com")
+			},
+			expectError: true,
+			expected:    []Endpoint{{BaseURL: "https://b.com"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			set := NewEndpointSet(initial)
+			base := newBalancedBaseURL(nil)
+			set.Watch(base)
+
+			// Act
+			err := tt.update(set)
+
+			// Assert
+			if (err != nil) != tt.expectError {
+				t.Fatalf("error got %v, want error %v", err, tt.expectError)
+			}
+			if got := set.Endpoints(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("endpoints got %v, want %v", got, tt.expected)
+			}
+			var got []string
+			for _, backend := range base.Backends() {
+				got = append(got, backend.URL.String())
+			}
+			var want []string
+			for _, endpoint := range tt.expected {
+				want = append(want, endpoint.BaseURL)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("balancer base URLs got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestEndpointSet_AddKnownEndpoint(t *testing.T) {
+	// Arrange
+	set := NewEndpointSet([]Endpoint{{BaseURL: "https://a.com", Zone: "us-east-1a"}, {BaseURL: "https://b.com"}})
+	base := newBalancedBaseURL(nil)
+	set.Watch(base)
+	known := base.Backends()[0].URL
+
+	// Act
+	err := set.Add(Endpoint{BaseURL: "https://a.com", Weight: 2, Zone: "us-east-1b", Priority: 1})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	backend := base.Backends()[0]
+	if backend.URL != known {
+		t.Errorf("base URL got %p, want the same URL %p", backend.URL, known)
+	}
+	if backend.Weight != 2 {
+		t.Errorf("weight got %v, want 2", backend.Weight)
+	}
+	if backend.Zone != "us-east-1b" {
+		t.Errorf("zone got %q, want %q", backend.Zone, "us-east-1b")
+	}
+	if backend.Priority != 1 {
+		t.Errorf("priority got %v, want 1", backend.Priority)
+	}
+}
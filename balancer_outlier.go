@@ -0,0 +1,197 @@
+package fastshot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Default values of OutlierDetectionConfig.
+const (
+	defaultOutlierConsecutiveFailures = 5
+	defaultOutlierBaseEjectionTime    = 30 * time.Second
+	defaultOutlierMaxEjectionTime     = 5 * time.Minute
+	defaultOutlierMaxEjectionPercent  = 10
+)
+
+type (
+	// OutlierDetectionConfig configures the passive outlier detection of load-balanced base URLs.
+	// Zero values fall back to the defaults documented on each field.
+	OutlierDetectionConfig struct {
+		// ConsecutiveFailures is the number of consecutive 5xx responses or transport errors
+		// that ejects a base URL. Defaults to 5.
+		ConsecutiveFailures uint
+		// BaseEjectionTime is how long the first ejection lasts. Each ejection that follows
+		// closely doubles it. Defaults to 30 seconds.
+		BaseEjectionTime time.Duration
+		// MaxEjectionTime caps the ejection time. Defaults to 5 minutes.
+		MaxEjectionTime time.Duration
+		// MaxEjectionPercent caps the share of base URLs ejected at the same time. At least one
+		// base URL can always be ejected, and at least one always stays. Defaults to 10.
+		MaxEjectionPercent uint
+	}
+
+	// OutlierDetector learns from live traffic which base URLs are failing and ejects them from
+	// rotation for a while, in the spirit of Envoy's outlier detection.
+	OutlierDetector struct {
+		config OutlierDetectionConfig
+		mu     sync.Mutex
+		state  map[*url.URL]*outlierState
+		now    func() time.Time
+	}
+
+	// outlierState tracks the recent failures and ejections of a base URL.
+	outlierState struct {
+		failures     uint
+		ejections    uint
+		ejectionTime time.Duration
+		ejectedUntil time.Time
+	}
+)
+
+// NewOutlierDetector creates an OutlierDetector with the given configuration.
+func NewOutlierDetector(config OutlierDetectionConfig) *OutlierDetector {
+	if config.ConsecutiveFailures == 0 {
+		config.ConsecutiveFailures = defaultOutlierConsecutiveFailures
+	}
+	if config.BaseEjectionTime <= 0 {
+		config.BaseEjectionTime = defaultOutlierBaseEjectionTime
+	}
+	if config.MaxEjectionTime <= 0 {
+		config.MaxEjectionTime = defaultOutlierMaxEjectionTime
+	}
+	if config.MaxEjectionTime < config.BaseEjectionTime {
+		config.MaxEjectionTime = config.BaseEjectionTime
+	}
+	if config.MaxEjectionPercent == 0 {
+		config.MaxEjectionPercent = defaultOutlierMaxEjectionPercent
+	}
+	return &OutlierDetector{
+		config: config,
+		state:  make(map[*url.URL]*outlierState),
+		now:    time.Now,
+	}
+}
+
+// Config for OutlierDetector returns the effective configuration.
+func (d *OutlierDetector) Config() OutlierDetectionConfig {
+	return d.config
+}
+
+// IsEjected for OutlierDetector reports whether the base URL is currently ejected.
+func (d *OutlierDetector) IsEjected(baseURL *url.URL) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	state, ok := d.state[baseURL]
+	return ok && d.now().Before(state.ejectedUntil)
+}
+
+// Record for OutlierDetector records the outcome of a request to one of the backends, ejecting
+// the base URL once it reaches the consecutive failure threshold.
+// The total is the number of backends, which bounds how many of them may be ejected at once.
+func (d *OutlierDetector) Record(baseURL *url.URL, total int, response *http.Response, err error) {
+	failed := isOutlierFailure(response, err)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state, ok := d.state[baseURL]
+	if !ok {
+		if !failed {
+			return
+		}
+		state = &outlierState{}
+		d.state[baseURL] = state
+	}
+
+	if !failed {
+		state.failures = 0
+		return
+	}
+
+	now := d.now()
+	state.failures++
+	if state.failures < d.config.ConsecutiveFailures || now.Before(state.ejectedUntil) {
+		return
+	}
+	if d.ejectedCount(now) >= d.maxEjections(total) {
+		return
+	}
+
+	// Ejections that follow closely double the ejection time; a base URL that stayed in
+	// rotation for longer than its last ejection starts over.
+	if state.ejections > 0 && now.Sub(state.ejectedUntil) > state.ejectionTime {
+		state.ejections = 0
+	}
+	state.ejections++
+	state.ejectionTime = min(d.config.BaseEjectionTime<<min(state.ejections-1, 30), d.config.MaxEjectionTime)
+	if state.ejectionTime <= 0 {
+		state.ejectionTime = d.config.MaxEjectionTime
+	}
+	state.ejectedUntil = now.Add(state.ejectionTime)
+	state.failures = 0
+}
+
+// filter returns the backends that are not ejected.
+func (d *OutlierDetector) filter(backends []Backend) []Backend {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	if d.ejectedCount(now) == 0 {
+		return backends
+	}
+
+	available := make([]Backend, 0, len(backends))
+	for _, backend := range backends {
+		if state, ok := d.state[backend.URL]; !ok || !now.Before(state.ejectedUntil) {
+			available = append(available, backend)
+		}
+	}
+	return available
+}
+
+// forget drops what the detector knows about the base URL, e.g. once it is no longer a member.
+func (d *OutlierDetector) forget(baseURL *url.URL) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.state, baseURL)
+}
+
+// ejectionEnd returns when the last ejection of the base URL ends, or the zero time if it was never ejected.
+func (d *OutlierDetector) ejectionEnd(baseURL *url.URL) time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if state, ok := d.state[baseURL]; ok {
+		return state.ejectedUntil
+	}
+	return time.Time{}
+}
+
+// ejectedCount returns the number of base URLs ejected at the given time. The caller must hold the lock.
+func (d *OutlierDetector) ejectedCount(now time.Time) int {
+	count := 0
+	for _, state := range d.state {
+		if now.Before(state.ejectedUntil) {
+			count++
+		}
+	}
+	return count
+}
+
+// maxEjections returns how many of the given number of base URLs may be ejected at the same time.
+func (d *OutlierDetector) maxEjections(total int) int {
+	return min(max(total*int(d.config.MaxEjectionPercent)/100, 1), total-1)
+}
+
+// isOutlierFailure reports whether the outcome counts against the backend: a transport error
+// other than a cancellation, or a 5xx response.
+func isOutlierFailure(response *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return response != nil && response.StatusCode >= http.StatusInternalServerError
+}
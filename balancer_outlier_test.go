@@ -0,0 +1,225 @@
+package fastshot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestNewOutlierDetector(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   OutlierDetectionConfig
+		expected OutlierDetectionConfig
+	}{
+		{
+			name:   "Defaults",
+			config: OutlierDetectionConfig{},
+			expected: OutlierDetectionConfig{
+				ConsecutiveFailures: 5,
+				BaseEjectionTime:    30 * time.Second,
+				MaxEjectionTime:     5 * time.Minute,
+				MaxEjectionPercent:  10,
+			},
+		},
+		{
+			name: "Max ejection time is at least the base ejection time",
+			config: OutlierDetectionConfig{
+				ConsecutiveFailures: 2,
+				BaseEjectionTime:    time.Minute,
+				MaxEjectionTime:     time.Second,
+				MaxEjectionPercent:  50,
+			},
+			expected: OutlierDetectionConfig{
+				ConsecutiveFailures: 2,
+				BaseEjectionTime:    time.Minute,
+				MaxEjectionTime:     time.Minute,
+				MaxEjectionPercent:  50,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			detector := NewOutlierDetector(tt.config)
+
+			// Assert
+			if got := detector.Config(); got != tt.expected {
+				t.Errorf("got %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOutlierDetector_Record(t *testing.T) {
+	transportErr := errors.New("connection refused")
+	serverError := &http.Response{StatusCode: http.StatusServiceUnavailable}
+	clientError := &http.Response{StatusCode: http.StatusNotFound}
+	success := &http.Response{StatusCode: http.StatusOK}
+
+	type outcome struct {
+		response *http.Response
+		err      error
+	}
+
+	tests := []struct {
+		name     string
+		outcomes []outcome
+		expected bool
+	}{
+		{
+			name:     "Consecutive 5xx responses eject",
+			outcomes: []outcome{{response: serverError}, {response: serverError}, {response: serverError}},
+			expected: true,
+		},
+		{
+			name:     "Consecutive transport errors eject",
+			outcomes: []outcome{{err: transportErr}, {err: transportErr}, {err: transportErr}},
+			expected: true,
+		},
+		{
+			name:     "Below threshold keeps the base URL",
+			outcomes: []outcome{{response: serverError}, {err: transportErr}},
+			expected: false,
+		},
+		{
+			name:     "Success resets the failures",
+			outcomes: []outcome{{response: serverError}, {response: serverError}, {response: success}, {response: serverError}},
+			expected: false,
+		},
+		{
+			name:     "4xx responses are not failures",
+			outcomes: []outcome{{response: clientError}, {response: clientError}, {response: clientError}},
+			expected: false,
+		},
+		{
+			name:     "Cancellations are not failures",
+			outcomes: []outcome{{err: context.Canceled}, {err: context.Canceled}, {err: context.Canceled}},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			backends := newTestBackends(1, 1)
+			detector := NewOutlierDetector(OutlierDetectionConfig{ConsecutiveFailures: 3})
+
+			// Act
+			for _, o := range tt.outcomes {
+				detector.Record(backends[0].URL, len(backends), o.response, o.err)
+			}
+
+			// Assert
+			if got := detector.IsEjected(backends[0].URL); got != tt.expected {
+				t.Errorf("ejected got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOutlierDetector_EjectionTime(t *testing.T) {
+	// Arrange
+	now := time.Now()
+	backends := newTestBackends(1, 1)
+	failing := backends[0].URL
+	detector := NewOutlierDetector(OutlierDetectionConfig{
+		ConsecutiveFailures: 1,
+		BaseEjectionTime:    10 * time.Second,
+		MaxEjectionTime:     30 * time.Second,
+	})
+	detector.now = func() time.Time { return now }
+	fail := func() {
+		detector.Record(failing, len(backends), nil, errors.New("connection refused"))
+	}
+
+	steps := []struct {
+		name     string
+		wait     time.Duration
+		expected time.Duration
+	}{
+		{name: "First ejection uses the base time", expected: 10 * time.Second},
+		{name: "Close second ejection doubles it", wait: 10 * time.Second, expected: 20 * time.Second},
+		{name: "Third ejection is capped", wait: 20 * time.Second, expected: 30 * time.Second},
+		{name: "Ejection starts over after a long recovery", wait: 61 * time.Second, expected: 10 * time.Second},
+	}
+
+	for _, step := range steps {
+		// Act
+		now = now.Add(step.wait)
+		fail()
+
+		// Assert
+		if !detector.IsEjected(failing) {
+			t.Fatalf("%s: got not ejected, want ejected", step.name)
+		}
+		now = now.Add(step.expected - time.Nanosecond)
+		if !detector.IsEjected(failing) {
+			t.Errorf("%s: got not ejected just before %v", step.name, step.expected)
+		}
+		now = now.Add(time.Nanosecond)
+		if detector.IsEjected(failing) {
+			t.Errorf("%s: got ejected after %v", step.name, step.expected)
+		}
+	}
+}
+
+func TestOutlierDetector_MaxEjectionPercent(t *testing.T) {
+	tests := []struct {
+		name       string
+		backends   int
+		percent    uint
+		failing    int
+		maxEjected int
+	}{
+		{name: "At least one base URL can be ejected", backends: 3, percent: 10, failing: 3, maxEjected: 1},
+		{name: "At least one base URL always stays", backends: 2, percent: 100, failing: 2, maxEjected: 1},
+		{name: "Percentage of a larger pool", backends: 10, percent: 30, failing: 5, maxEjected: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			weights := make([]float64, tt.backends)
+			for i := range weights {
+				weights[i] = 1
+			}
+			backends := newTestBackends(weights...)
+			detector := NewOutlierDetector(OutlierDetectionConfig{ConsecutiveFailures: 1, MaxEjectionPercent: tt.percent})
+
+			// Act
+			for _, backend := range backends[:tt.failing] {
+				detector.Record(backend.URL, len(backends), nil, errors.New("connection refused"))
+			}
+
+			// Assert
+			if got := len(backends) - len(detector.filter(backends)); got != tt.maxEjected {
+				t.Errorf("ejected got %d, want %d", got, tt.maxEjected)
+			}
+		})
+	}
+}
+
+func TestBalancedBaseURL_OutlierDetection(t *testing.T) {
+	// Arrange
+	u1, _ := url.Parse("https://a.com")
+	u2, _ := url.Parse("https://b.com")
+	u3, _ := url.Parse("https://c.com")
+	base := newBalancedBaseURL([]*url.URL{u1, u2, u3})
+	base.SetOutlierDetector(NewOutlierDetector(OutlierDetectionConfig{ConsecutiveFailures: 2}))
+
+	// Act
+	base.RecordOutcome(u2, &http.Response{StatusCode: http.StatusBadGateway}, nil)
+	base.RecordOutcome(u2, &http.Response{StatusCode: http.StatusBadGateway}, nil)
+
+	// Assert
+	for i, want := range []*url.URL{u1, u3, u1, u3} {
+		if got := base.BaseURL(); got != want {
+			t.Errorf("selection %d got %v, want %v", i, got, want)
+		}
+	}
+}
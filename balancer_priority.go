@@ -0,0 +1,78 @@
+package fastshot
+
+type (
+	// PriorityGroupsConfig configures how backends are grouped by priority and zone.
+	//
+	// Backends are ranked by Endpoint.Priority, lowest first. With a LocalZone, the backends of
+	// that zone rank ahead of the other backends of the same priority. Traffic goes to the
+	// highest-ranked group with enough available backends, and spills over to the next group
+	// otherwise. Zero values fall back to the defaults documented on each field.
+	PriorityGroupsConfig struct {
+		// LocalZone is the zone of the client. Backends of this zone are preferred over the
+		// backends of other zones with the same priority. Defaults to no zone preference.
+		LocalZone string
+		// MinHealthyPercent is the share of the backends of a group that must be available for
+		// the group to take traffic. Defaults to 0: a single available backend is enough.
+		MinHealthyPercent uint
+	}
+)
+
+// PriorityGroups for BalancedBaseURL returns the priority groups configuration.
+func (c *BalancedBaseURL) PriorityGroups() PriorityGroupsConfig {
+	return c.groups
+}
+
+// SetPriorityGroups for BalancedBaseURL sets the priority groups configuration.
+func (c *BalancedBaseURL) SetPriorityGroups(config PriorityGroupsConfig) {
+	c.groups = config
+}
+
+// priorityGroup returns the candidates of the highest-ranked group with enough available
+// backends. Group sizes are taken from all the backends, so candidates missing from a group
+// count as unavailable. When no group has enough, the highest-ranked group with any candidate
+// is used.
+func (c *BalancedBaseURL) priorityGroup(backends, candidates []Backend) []Backend {
+	first := c.groups.rank(candidates[0])
+	best, uniform := first, true
+	for _, backend := range candidates[1:] {
+		rank := c.groups.rank(backend)
+		best = min(best, rank)
+		uniform = uniform && rank == first
+	}
+	if uniform {
+		return candidates
+	}
+
+	totals := make(map[uint]int)
+	for _, backend := range backends {
+		totals[c.groups.rank(backend)]++
+	}
+	available := make(map[uint]int)
+	for _, backend := range candidates {
+		available[c.groups.rank(backend)]++
+	}
+
+	chosen, found := best, false
+	for rank, count := range available {
+		if count*100 >= int(c.groups.MinHealthyPercent)*totals[rank] && (!found || rank < chosen) {
+			chosen, found = rank, true
+		}
+	}
+
+	group := make([]Backend, 0, available[chosen])
+	for _, backend := range candidates {
+		if c.groups.rank(backend) == chosen {
+			group = append(group, backend)
+		}
+	}
+	return group
+}
+
+// rank orders the backend among the priority groups, lowest first.
+func (c PriorityGroupsConfig) rank(backend Backend) uint {
+	rank := backend.Priority * 2
+	if c.LocalZone != "" && backend.Zone != c.LocalZone {
+		rank++
+	}
+	return rank
+}
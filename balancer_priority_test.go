@@ -0,0 +1,104 @@
+package fastshot
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestBalancedBaseURL_PriorityGroups(t *testing.T) {
+	endpoints := []Endpoint{
+		{BaseURL: "https://a1.com", Zone: "eu-1"},
+		{BaseURL: "https://a2.com", Zone: "eu-1"},
+		{BaseURL: "https://b1.com", Zone: "eu-2"},
+		{BaseURL: "https://c1.com", Zone: "us-1", Priority: 1},
+	}
+
+	tests := []struct {
+		name      string
+		config    PriorityGroupsConfig
+		unhealthy []string
+		exclude   []string
+		expected  []string
+	}{
+		{
+			name:     "Priority 0 without zone preference",
+			expected: []string{"https://a1.com", "https://a2.com", "https://b1.com"},
+		},
+		{
+			name:     "Local zone first",
+			config:   PriorityGroupsConfig{LocalZone: "eu-1"},
+			expected: []string{"https://a1.com", "https://a2.com"},
+		},
+		{
+			name:      "Local zone with one available backend",
+			config:    PriorityGroupsConfig{LocalZone: "eu-1"},
+			unhealthy: []string{"https://a1.com"},
+			expected:  []string{"https://a2.com"},
+		},
+		{
+			name:      "Spill over to the other zones",
+			config:    PriorityGroupsConfig{LocalZone: "eu-1"},
+			unhealthy: []string{"https://a1.com", "https://a2.com"},
+			expected:  []string{"https://b1.com"},
+		},
+		{
+			name:      "Spill over below the minimum healthy share",
+			config:    PriorityGroupsConfig{LocalZone: "eu-1", MinHealthyPercent: 100},
+			unhealthy: []string{"https://a1.com"},
+			expected:  []string{"https://b1.com"},
+		},
+		{
+			name:      "Spill over to the next priority",
+			unhealthy: []string{"https://a1.com", "https://a2.com", "https://b1.com"},
+			expected:  []string{"https://c1.com"},
+		},
+		{
+			name:     "Excluded backends count as unavailable",
+			config:   PriorityGroupsConfig{LocalZone: "eu-1"},
+			exclude:  []string{"https://a1.com", "https://a2.com"},
+			expected: []string{"https://b1.com"},
+		},
+		{
+			name:      "Highest group with any available backend when none has enough",
+			config:    PriorityGroupsConfig{MinHealthyPercent: 100},
+			unhealthy: []string{"https://a1.com", "https://c1.com"},
+			expected:  []string{"https://a2.com", "https://b1.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			base := newBalancedBaseURL(nil)
+			if err := base.SetEndpoints(endpoints); err != nil {
+				t.Fatal(err)
+			}
+			base.SetStrategy(NewRoundRobinStrategy())
+			base.SetPriorityGroups(tt.config)
+			for _, backend := range base.Backends() {
+				for _, unhealthy := range tt.unhealthy {
+					if backend.URL.String() == unhealthy {
+						base.SetHealthy(backend.URL, false)
+					}
+				}
+			}
+			var criteria BaseURLCriteria
+			for _, excluded := range tt.exclude {
+				u, _ := url.Parse(excluded)
+				criteria.Exclude = append(criteria.Exclude, u)
+			}
+
+			// Act
+			var got []string
+			for range len(tt.expected) {
+				got = append(got, base.SelectBaseURL(criteria).String())
+			}
+
+			// Assert
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
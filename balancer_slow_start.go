@@ -0,0 +1,108 @@
+package fastshot
+
+import (
+	"math"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// Default values of SlowStartConfig.
+const (
+	defaultSlowStartWindow           = 30 * time.Second
+	defaultSlowStartMinWeightPercent = 10
+	defaultSlowStartAggression       = 1.0
+)
+
+type (
+	// SlowStartConfig configures the slow start of load-balanced base URLs. A base URL that joins
+	// the rotation, because it was added, marked healthy again or its ejection ended, starts with
+	// a fraction of its weight that ramps up to the full weight over the window. Zero values fall
+	// back to the defaults documented on each field.
+	//
+	// The effective weight is weight * max(MinWeightPercent/100, (elapsed/Window)^(1/Aggression)).
+	// Strategies that use weights, such as weighted round-robin, least outstanding requests and
+	// consistent hashing, follow the ramp. Round-robin ignores it.
+	SlowStartConfig struct {
+		// Window is how long the ramp lasts. Defaults to 30 seconds.
+		Window time.Duration
+		// MinWeightPercent is the share of the weight a base URL starts with. Defaults to 10.
+		MinWeightPercent uint
+		// Aggression shapes the ramp: 1 is linear, higher values ramp up faster at the start and
+		// lower values slower. Defaults to 1.
+		Aggression float64
+	}
+)
+
+// SlowStart for BalancedBaseURL returns the slow start configuration. A zero window means slow
+// start is disabled.
+func (c *BalancedBaseURL) SlowStart() SlowStartConfig {
+	return c.slowStart
+}
+
+// SetSlowStart for BalancedBaseURL enables slow start with the given configuration.
+func (c *BalancedBaseURL) SetSlowStart(config SlowStartConfig) {
+	if config.Window <= 0 {
+		config.Window = defaultSlowStartWindow
+	}
+	if config.MinWeightPercent == 0 {
+		config.MinWeightPercent = defaultSlowStartMinWeightPercent
+	}
+	if config.Aggression <= 0 {
+		config.Aggression = defaultSlowStartAggression
+	}
+	c.slowStart = config
+}
+
+// markJoined records that the base URL joined the rotation now, when slow start is enabled.
+// The caller must hold the lock.
+func (c *BalancedBaseURL) markJoined(baseURL *url.URL) {
+	if c.slowStart.Window <= 0 {
+		return
+	}
+	if c.joined == nil {
+		c.joined = make(map[*url.URL]time.Time)
+	}
+	c.joined[baseURL] = c.now()
+}
+
+// rampWeights returns the candidates with the weights of the base URLs in their slow start
+// window reduced. The given slice is not modified.
+func (c *BalancedBaseURL) rampWeights(candidates []Backend) []Backend {
+	config := c.slowStart
+	if config.Window <= 0 {
+		return candidates
+	}
+
+	now := c.now()
+	var ramped []Backend
+	for index, backend := range candidates {
+		start := c.joinedAt(backend.URL)
+		if c.outliers != nil {
+			if end := c.outliers.ejectionEnd(backend.URL); end.After(start) {
+				start = end
+			}
+		}
+		elapsed := now.Sub(start)
+		if start.IsZero() || elapsed >= config.Window || elapsed < 0 {
+			continue
+		}
+
+		if ramped == nil {
+			ramped = slices.Clone(candidates)
+		}
+		factor := math.Pow(float64(elapsed)/float64(config.Window), 1/config.Aggression)
+		ramped[index].Weight = backend.Weight * max(float64(config.MinWeightPercent)/100, factor)
+	}
+	if ramped == nil {
+		return candidates
+	}
+	return ramped
+}
+
+// joinedAt returns when the base URL last joined the rotation, or the zero time if unknown.
+func (c *BalancedBaseURL) joinedAt(baseURL *url.URL) time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.joined[baseURL]
+}
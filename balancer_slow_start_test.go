@@ -0,0 +1,175 @@
+package fastshot
+
+import (
+	"math"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestBalancedBaseURL_RampWeights(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *SlowStartConfig
+		elapsed  time.Duration
+		expected float64
+	}{
+		{
+			name:     "Disabled",
+			elapsed:  time.Second,
+			expected: 4,
+		},
+		{
+			name:     "Linear ramp",
+			config:   &SlowStartConfig{Window: 10 * time.Second},
+			elapsed:  5 * time.Second,
+			expected: 2,
+		},
+		{
+			name:     "Minimum weight at the start",
+			config:   &SlowStartConfig{Window: 10 * time.Second, MinWeightPercent: 25},
+			elapsed:  time.Second,
+			expected: 1,
+		},
+		{
+			name:     "Aggressive ramp",
+			config:   &SlowStartConfig{Window: 10 * time.Second, Aggression: 2},
+			elapsed:  2500 * time.Millisecond,
+			expected: 2,
+		},
+		{
+			name:     "Full weight after the window",
+			config:   &SlowStartConfig{Window: 10 * time.Second},
+			elapsed:  10 * time.Second,
+			expected: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			now := time.Unix(1_000, 0)
+			base := newBalancedBaseURL(nil)
+			base.now = func() time.Time { return now }
+			if tt.config != nil {
+				base.SetSlowStart(*tt.config)
+			}
+			_ = base.SetEndpoints([]Endpoint{{BaseURL: "https://a.com", Weight: 4}})
+			_ = base.SetEndpoints([]Endpoint{{BaseURL: "https://a.com", Weight: 4}, {BaseURL: "https://b.com", Weight: 4}})
+			now = now.Add(tt.elapsed)
+
+			// Act
+			ramped := base.rampWeights(base.Backends())
+
+			// Assert
+			if got := ramped[0].Weight; got != 4 {
+				t.Errorf("known backend weight got %v, want 4", got)
+			}
+			if got := ramped[1].Weight; math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("new backend weight got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBalancedBaseURL_SlowStartTriggers(t *testing.T) {
+	tests := []struct {
+		name   string
+		rejoin func(base *BalancedBaseURL, now *time.Time)
+	}{
+		{
+			name: "Marked healthy again",
+			rejoin: func(base *BalancedBaseURL, _ *time.Time) {
+				b := base.Backends()[1].URL
+				base.SetHealthy(b, false)
+				base.SetHealthy(b, true)
+			},
+		},
+		{
+			name: "Back from an ejection",
+			rejoin: func(base *BalancedBaseURL, now *time.Time) {
+				detector := NewOutlierDetector(OutlierDetectionConfig{ConsecutiveFailures: 1, BaseEjectionTime: time.Second, MaxEjectionPercent: 50})
+				detector.now = func() time.Time { return *now }
+				base.SetOutlierDetector(detector)
+				base.RecordOutcome(base.Backends()[1].URL, &http.Response{StatusCode: http.StatusInternalServerError}, nil)
+				*now = now.Add(time.Second)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			now := time.Unix(1_000, 0)
+			u1, _ := url.Parse("https://a.com")
+			u2, _ := url.Parse("https://b.com")
+			base := newBalancedBaseURL([]*url.URL{u1, u2})
+			base.now = func() time.Time { return now }
+			base.SetSlowStart(SlowStartConfig{Window: 10 * time.Second})
+
+			// Act
+			tt.rejoin(base, &now)
+			now = now.Add(5 * time.Second)
+			ramped := base.rampWeights(base.Backends())
+
+			// Assert
+			if got := ramped[0].Weight; got != 1 {
+				t.Errorf("steady backend weight got %v, want 1", got)
+			}
+			if got := ramped[1].Weight; got != 0.5 {
+				t.Errorf("rejoined backend weight got %v, want 0.5", got)
+			}
+		})
+	}
+}
+
+func TestBalancedBaseURL_SlowStartStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy func() LoadBalancingStrategy
+		rounds   int
+		expected int
+	}{
+		{
+			name:     "Weighted round robin",
+			strategy: func() LoadBalancingStrategy { return NewWeightedRoundRobinStrategy() },
+			rounds:   110,
+			expected: 10,
+		},
+		{
+			name:     "Least outstanding requests",
+			strategy: func() LoadBalancingStrategy { return NewLeastOutstandingStrategy() },
+			rounds:   11,
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			now := time.Unix(1_000, 0)
+			base := newBalancedBaseURL(nil)
+			base.now = func() time.Time { return now }
+			base.SetStrategy(tt.strategy())
+			base.SetSlowStart(SlowStartConfig{Window: 10 * time.Second, MinWeightPercent: 10})
+			_ = base.SetEndpoints([]Endpoint{{BaseURL: "https://a.com"}})
+			_ = base.SetEndpoints([]Endpoint{{BaseURL: "https://a.com"}, {BaseURL: "https://b.com"}})
+
+			// Act
+			got := 0
+			for range tt.rounds {
+				selected := base.SelectBaseURL(BaseURLCriteria{})
+				base.RequestStarted(selected) // requests stay outstanding
+				if selected.Host == "b.com" {
+					got++
+				}
+			}
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("new backend selections got %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package fastshot
+
+import (
+	"math/rand/v2"
+	"net/url"
+	"sync"
+	"sync/atomic"
+)
+
+// Compile-time checks that the built-in strategies implement LoadBalancingStrategy.
+var (
+	_ LoadBalancingStrategy = (*RoundRobinStrategy)(nil)
+	_ LoadBalancingStrategy = (*WeightedRoundRobinStrategy)(nil)
+	_ LoadBalancingStrategy = (*LeastOutstandingStrategy)(nil)
+	_ LoadBalancingStrategy = (*PowerOfTwoChoicesStrategy)(nil)
+)
+
+type (
+	// Backend describes a load-balanced base URL as seen by a LoadBalancingStrategy.
+	Backend struct {
+		// URL is the base URL of the backend. The same pointer is passed to Start and Finish.
+		URL *url.URL
+		// Weight is the relative share of traffic of the backend. It is always greater than zero.
+		Weight float64
+		// Priority is the priority group of the backend, lowest first.
+		Priority uint
+		// Zone is the locality of the backend, if known.
+		Zone string
+	}
+
+	// RoundRobinStrategy cycles through the backends in order, ignoring weights.
+	RoundRobinStrategy struct {
+		next atomic.Uint32
+	}
+
+	// WeightedRoundRobinStrategy implements smooth weighted round-robin (as in nginx): every
+	// backend gains its weight, the one with the highest current weight wins and gives back the
+	// total, which spreads each backend's share evenly over the rotation. When every weight is
+	// the same, it falls back to a lock-free round-robin.
+	WeightedRoundRobinStrategy struct {
+		mu      sync.Mutex
+		current map[*url.URL]float64
+		next    atomic.Uint32
+	}
+
+	// LeastOutstandingStrategy picks the backend with the fewest in-flight requests relative to
+	// its weight. Ties are broken in round-robin order.
+	LeastOutstandingStrategy struct {
+		outstandingTracker
+		next atomic.Uint32
+	}
+
+	// PowerOfTwoChoicesStrategy picks two backends at random and takes the one with fewer
+	// in-flight requests relative to its weight.
+	PowerOfTwoChoicesStrategy struct {
+		outstandingTracker
+		intN func(n int) int
+	}
+
+	// backendForgetter is implemented by the strategies that keep state per backend, which is
+	// dropped once the backend leaves the rotation.
+	backendForgetter interface {
+		forget(baseURL *url.URL)
+	}
+
+	// outstandingTracker counts the in-flight requests of each backend.
+	outstandingTracker struct {
+		mu          sync.Mutex
+		outstanding map[*url.URL]int
+	}
+)
+
+// NewRoundRobinStrategy creates a strategy that cycles through the backends in order.
+func NewRoundRobinStrategy() *RoundRobinStrategy {
+	return &RoundRobinStrategy{}
+}
+
+// Select for RoundRobinStrategy returns the next backend in the rotation.
+func (s *RoundRobinStrategy) Select(backends []Backend, _ BaseURLCriteria) int {
+	return int((s.next.Add(1) - 1) % uint32(len(backends)))
+}
+
+// Start for RoundRobinStrategy does nothing.
+func (s *RoundRobinStrategy) Start(*url.URL) {}
+
+// Finish for RoundRobinStrategy does nothing.
+func (s *RoundRobinStrategy) Finish(*url.URL) {}
+
+// NewWeightedRoundRobinStrategy creates a strategy that splits traffic in proportion to the backend weights.
+func NewWeightedRoundRobinStrategy() *WeightedRoundRobinStrategy {
+	return &WeightedRoundRobinStrategy{current: make(map[*url.URL]float64)}
+}
+
+// Select for WeightedRoundRobinStrategy returns the backend with the highest current weight.
+func (s *WeightedRoundRobinStrategy) Select(backends []Backend, _ BaseURLCriteria) int {
+	if uniformWeights(backends) {
+		return int((s.next.Add(1) - 1) % uint32(len(backends)))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	best, total := 0, 0.0
+	for index, backend := range backends {
+		s.current[backend.URL] += backend.Weight
+		total += backend.Weight
+		if s.current[backend.URL] > s.current[backends[best].URL] {
+			best = index
+		}
+	}
+	s.current[backends[best].URL] -= total
+	return best
+}
+
+// uniformWeights reports whether every backend has the same weight.
+func uniformWeights(backends []Backend) bool {
+	for _, backend := range backends[1:] {
+		if backend.Weight != backends[0].Weight {
+			return false
+		}
+	}
+	return true
+}
+
+// Start for WeightedRoundRobinStrategy does nothing.
+func (s *WeightedRoundRobinStrategy) Start(*url.URL) {}
+
+// Finish for WeightedRoundRobinStrategy does nothing.
+func (s *WeightedRoundRobinStrategy) Finish(*url.URL) {}
+
+// forget for WeightedRoundRobinStrategy drops the current weight of a backend that left the rotation.
+func (s *WeightedRoundRobinStrategy) forget(baseURL *url.URL) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.current, baseURL)
+}
+
+// NewLeastOutstandingStrategy creates a strategy that sends each request to the least busy backend.
+func NewLeastOutstandingStrategy() *LeastOutstandingStrategy {
+	return &LeastOutstandingStrategy{
+		outstandingTracker: outstandingTracker{outstanding: make(map[*url.URL]int)},
+	}
+}
+
+// Select for LeastOutstandingStrategy returns the backend with the lowest load.
+func (s *LeastOutstandingStrategy) Select(backends []Backend, _ BaseURLCriteria) int {
+	count := uint32(len(backends))
+	offset := s.next.Add(1) - 1
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	best := int(offset % count)
+	for step := range count {
+		index := int((offset + step) % count)
+		if s.load(backends[index]) < s.load(backends[best]) {
+			best = index
+		}
+	}
+	return best
+}
+
+// NewPowerOfTwoChoicesStrategy creates a strategy that compares two random backends per request.
+func NewPowerOfTwoChoicesStrategy() *PowerOfTwoChoicesStrategy {
+	return &PowerOfTwoChoicesStrategy{
+		outstandingTracker: outstandingTracker{outstanding: make(map[*url.URL]int)},
+		intN:               rand.IntN,
+	}
+}
+
+// Select for PowerOfTwoChoicesStrategy returns the less loaded of two randomly chosen backends.
+func (s *PowerOfTwoChoicesStrategy) Select(backends []Backend, _ BaseURLCriteria) int {
+	if len(backends) == 1 {
+		return 0
+	}
+
+	first := s.intN(len(backends))
+	second := s.intN(len(backends) - 1)
+	if second >= first {
+		second++
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.load(backends[second]) < s.load(backends[first]) {
+		return second
+	}
+	return first
+}
+
+// Start records a new in-flight request to the base URL.
+func (t *outstandingTracker) Start(baseURL *url.URL) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.outstanding[baseURL]++
+}
+
+// Finish records the end of an in-flight request to the base URL.
+func (t *outstandingTracker) Finish(baseURL *url.URL) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.outstanding[baseURL] <= 1 {
+		delete(t.outstanding, baseURL)
+		return
+	}
+	t.outstanding[baseURL]--
+}
+
+// load returns the in-flight requests of the backend, including the one being placed, scaled
+// by its weight. The caller must hold the lock.
+func (t *outstandingTracker) load(backend Backend) float64 {
+	return float64(t.outstanding[backend.URL]+1) / backend.Weight
+}
@@ -0,0 +1,198 @@
+package fastshot
+
+import (
+	"net/url"
+	"testing"
+)
+
+func newTestBackends(weights ...float64) []Backend {
+	backends := make([]Backend, len(weights))
+	for index, weight := range weights {
+		u, _ := url.Parse("https://" + string(rune('a'+index)) + ".com")
+		backends[index] = Backend{URL: u, Weight: weight}
+	}
+	return backends
+}
+
+func TestRoundRobinStrategy(t *testing.T) {
+	// Arrange
+	backends := newTestBackends(1, 5, 1)
+	strategy := NewRoundRobinStrategy()
+
+	// Act & Assert
+	for i, want := range []int{0, 1, 2, 0, 1} {
+		if got := strategy.Select(backends, BaseURLCriteria{}); got != want {
+			t.Errorf("selection %d got %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestWeightedRoundRobinStrategy(t *testing.T) {
+	// Arrange
+	backends := newTestBackends(5, 1, 1)
+	strategy := NewWeightedRoundRobinStrategy()
+
+	// Act & Assert
+	for i, want := range []int{0, 0, 1, 0, 2, 0, 0} {
+		if got := strategy.Select(backends, BaseURLCriteria{}); got != want {
+			t.Errorf("selection %d got %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestWeightedRoundRobinStrategy_NarrowedSelections(t *testing.T) {
+	// Arrange
+	backends := newTestBackends(5, 1, 1, 1, 1)
+	strategy := NewWeightedRoundRobinStrategy()
+
+	// Act
+	counts := make(map[int]int)
+	for range 600 {
+		strategy.Select(backends, BaseURLCriteria{})
+		counts[strategy.Select(backends[:2], BaseURLCriteria{})]++
+	}
+
+	// Assert
+	if got := counts[1]; got < 90 || got > 110 {
+		t.Errorf("narrowed selections of the weight 1 backend got %d of 600, want about 100", got)
+	}
+}
+
+func TestLeastOutstandingStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		backends []Backend
+		started  []int
+		finished []int
+		expected int
+	}{
+		{
+			name:     "Idle backends are picked in round robin order",
+			backends: newTestBackends(1, 1, 1),
+			expected: 0,
+		},
+		{
+			name:     "Busy backends are avoided",
+			backends: newTestBackends(1, 1, 1),
+			started:  []int{0, 0, 1},
+			expected: 2,
+		},
+		{
+			name:     "Finished requests no longer count",
+			backends: newTestBackends(1, 1),
+			started:  []int{0, 1, 1},
+			finished: []int{1, 1},
+			expected: 1,
+		},
+		{
+			name:     "Load is relative to weight",
+			backends: newTestBackends(1, 4),
+			started:  []int{1, 1},
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			strategy := NewLeastOutstandingStrategy()
+			for _, index := range tt.started {
+				strategy.Start(tt.backends[index].URL)
+			}
+			for _, index := range tt.finished {
+				strategy.Finish(tt.backends[index].URL)
+			}
+
+			// Act
+			got := strategy.Select(tt.backends, BaseURLCriteria{})
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("got %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLeastOutstandingStrategy_FinishCleansUp(t *testing.T) {
+	// Arrange
+	backends := newTestBackends(1)
+	strategy := NewLeastOutstandingStrategy()
+
+	// Act
+	strategy.Start(backends[0].URL)
+	strategy.Finish(backends[0].URL)
+	strategy.Finish(backends[0].URL)
+
+	// Assert
+	if got := len(strategy.outstanding); got != 0 {
+		t.Errorf("tracked backends got %d, want 0", got)
+	}
+}
+
+func TestPowerOfTwoChoicesStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		backends []Backend
+		choices  []int
+		started  []int
+		expected int
+	}{
+		{
+			name:     "Single backend",
+			backends: newTestBackends(1),
+			expected: 0,
+		},
+		{
+			name:     "First choice wins ties",
+			backends: newTestBackends(1, 1, 1),
+			choices:  []int{2, 0},
+			expected: 2,
+		},
+		{
+			name:     "Less loaded choice wins",
+			backends: newTestBackends(1, 1, 1),
+			choices:  []int{2, 0},
+			started:  []int{2},
+			expected: 0,
+		},
+		{
+			name:     "Second choice skips the first",
+			backends: newTestBackends(1, 1, 1),
+			choices:  []int{0, 0},
+			started:  []int{0},
+			expected: 1,
+		},
+		{
+			name:     "Most loaded backend is never compared against itself",
+			backends: newTestBackends(1, 1, 1),
+			choices:  []int{1, 1},
+			started:  []int{1, 1},
+			expected: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			strategy := NewPowerOfTwoChoicesStrategy()
+			choices := tt.choices
+			strategy.intN = func(n int) int {
+				choice := choices[0]
+				choices = choices[1:]
+				return choice
+			}
+			for _, index := range tt.started {
+				strategy.Start(tt.backends[index].URL)
+			}
+
+			// Act
+			got := strategy.Select(tt.backends, BaseURLCriteria{})
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("got %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
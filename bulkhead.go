@@ -0,0 +1,230 @@
+package fastshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/opus-domini/fast-shot/constant"
+)
+
+// ErrBulkheadFull is returned, wrapped in a BulkheadError, when a request is rejected because the
+// bulkhead has no free slot.
+var ErrBulkheadFull = errors.New(constant.ErrMsgBulkheadFull)
+
+// Default values of BulkheadConfig.
+const (
+	defaultBulkheadMaxConcurrent = 100
+	defaultBulkheadMinConcurrent = 1
+	defaultBulkheadBackoffRatio  = 0.5
+)
+
+type (
+	// BulkheadConfig configures a Bulkhead. Zero values fall back to the defaults documented on
+	// each field.
+	BulkheadConfig struct {
+		// MaxConcurrent is the maximum number of requests in flight. Defaults to 100. In adaptive
+		// mode, it is the upper bound of the limit, which also starts there.
+		MaxConcurrent uint
+		// MaxQueue is the number of requests that may wait for a slot. Defaults to 0: requests
+		// are rejected as soon as every slot is taken.
+		MaxQueue uint
+		// QueueTimeout bounds the time a request waits in the queue. Defaults to 0: requests wait
+		// until their context is done.
+		QueueTimeout time.Duration
+		// Adaptive adjusts the limit to the health of the dependency, AIMD style: it grows by one
+		// slot per limit successful requests, and is multiplied by BackoffRatio on a 5xx response,
+		// a transport error or a response slower than LatencyThreshold.
+		Adaptive bool
+		// MinConcurrent is the lower bound of the limit in adaptive mode. Defaults to 1.
+		MinConcurrent uint
+		// LatencyThreshold marks slower responses as a congestion signal in adaptive mode.
+		// Defaults to 0: latency is ignored.
+		LatencyThreshold time.Duration
+		// BackoffRatio is the factor applied to the limit on a congestion signal in adaptive mode.
+		// Defaults to 0.5.
+		BackoffRatio float64
+	}
+
+	// Bulkhead caps the number of requests in flight, so a slow dependency cannot use up every
+	// goroutine and connection. Requests over the limit wait in a bounded queue, or are rejected
+	// with a BulkheadError. It is safe for concurrent use by every request of a client.
+	Bulkhead struct {
+		config       BulkheadConfig
+		mu           sync.Mutex
+		limit        float64
+		inflight     uint
+		waiters      []chan struct{}
+		lastDecrease time.Time
+		now          func() time.Time
+	}
+
+	// BulkheadError is the error returned when the bulkhead rejects a request. It matches
+	// ErrBulkheadFull with errors.Is.
+	BulkheadError struct {
+		// Limit is the concurrency limit when the request was rejected.
+		Limit uint
+		// QueueTimeout reports whether the request was rejected after waiting in the queue,
+		// rather than because the queue was full.
+		QueueTimeout bool
+	}
+)
+
+// Error returns the error message.
+func (e *BulkheadError) Error() string {
+	if e.QueueTimeout {
+		return fmt.Sprintf("%s: no slot within the queue timeout (limit %d)", constant.ErrMsgBulkheadFull, e.Limit)
+	}
+	return fmt.Sprintf("%s: queue full (limit %d)", constant.ErrMsgBulkheadFull, e.Limit)
+}
+
+// Unwrap returns ErrBulkheadFull.
+func (e *BulkheadError) Unwrap() error {
+	return ErrBulkheadFull
+}
+
+// NewBulkhead creates a Bulkhead with the given configuration.
+func NewBulkhead(config BulkheadConfig) *Bulkhead {
+	if config.MaxConcurrent == 0 {
+		config.MaxConcurrent = defaultBulkheadMaxConcurrent
+	}
+	if config.MinConcurrent == 0 {
+		config.MinConcurrent = defaultBulkheadMinConcurrent
+	}
+	config.MinConcurrent = min(config.MinConcurrent, config.MaxConcurrent)
+	if config.BackoffRatio <= 0 || config.BackoffRatio >= 1 {
+		config.BackoffRatio = defaultBulkheadBackoffRatio
+	}
+	return &Bulkhead{
+		config: config,
+		limit:  float64(config.MaxConcurrent),
+		now:    time.Now,
+	}
+}
+
+// Config returns the effective configuration.
+func (b *Bulkhead) Config() BulkheadConfig {
+	return b.config
+}
+
+// Limit returns the current concurrency limit.
+func (b *Bulkhead) Limit() uint {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return uint(b.limit)
+}
+
+// InFlight returns the number of requests holding a slot.
+func (b *Bulkhead) InFlight() uint {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.inflight
+}
+
+// Acquire takes a slot, waiting in the queue when every slot is taken. It returns a BulkheadError
+// when the queue is full or the queue timeout expires, and the context error when the context is
+// done first. Otherwise, it returns the function that frees the slot, which must be called
+// exactly once.
+func (b *Bulkhead) Acquire(ctx context.Context) (func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	if len(b.waiters) == 0 && b.inflight < uint(b.limit) {
+		b.inflight++
+		b.mu.Unlock()
+		return b.releaser(), nil
+	}
+	if uint(len(b.waiters)) >= b.config.MaxQueue {
+		limit := uint(b.limit)
+		b.mu.Unlock()
+		return nil, &BulkheadError{Limit: limit}
+	}
+	granted := make(chan struct{})
+	b.waiters = append(b.waiters, granted)
+	b.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if b.config.QueueTimeout > 0 {
+		timer := time.NewTimer(b.config.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-granted:
+		return b.releaser(), nil
+	case <-ctx.Done():
+		return nil, b.abandon(granted, ctx.Err())
+	case <-timeout:
+		return nil, b.abandon(granted, &BulkheadError{Limit: b.Limit(), QueueTimeout: true})
+	}
+}
+
+// Record feeds the outcome of a request started at the given time to the adaptive limit. It does
+// nothing unless the bulkhead is adaptive. Cancellations are ignored, and a congestion signal
+// only lowers the limit once for the requests started before the previous decrease.
+func (b *Bulkhead) Record(start time.Time, response *http.Response, err error) {
+	if !b.config.Adaptive || (response == nil && err == nil) {
+		return
+	}
+	failure := isOutlierFailure(response, err)
+	if err != nil && !failure {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if failure || (b.config.LatencyThreshold > 0 && now.Sub(start) > b.config.LatencyThreshold) {
+		if start.Before(b.lastDecrease) {
+			return
+		}
+		b.limit = max(float64(b.config.MinConcurrent), b.limit*b.config.BackoffRatio)
+		b.lastDecrease = now
+		return
+	}
+	b.limit = min(float64(b.config.MaxConcurrent), b.limit+1/b.limit)
+	b.dispatch()
+}
+
+// releaser returns the function that frees a slot once.
+func (b *Bulkhead) releaser() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.inflight--
+			b.dispatch()
+		})
+	}
+}
+
+// abandon takes the waiter out of the queue. When the slot was granted in the meantime, it is
+// handed over to the next waiter. It returns the given error.
+func (b *Bulkhead) abandon(granted chan struct{}, err error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if index := slices.Index(b.waiters, granted); index >= 0 {
+		b.waiters = slices.Delete(b.waiters, index, index+1)
+		return err
+	}
+	b.inflight--
+	b.dispatch()
+	return err
+}
+
+// dispatch grants the free slots to the waiters, in order. The caller must hold the lock.
+func (b *Bulkhead) dispatch() {
+	for len(b.waiters) > 0 && b.inflight < uint(b.limit) {
+		close(b.waiters[0])
+		b.waiters = b.waiters[1:]
+		b.inflight++
+	}
+}
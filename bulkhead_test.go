@@ -0,0 +1,208 @@
+package fastshot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBulkhead_Acquire(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        BulkheadConfig
+		ctx           func() (context.Context, context.CancelFunc)
+		expectedError error
+		queueTimeout  bool
+	}{
+		{
+			name:          "Rejected without a queue",
+			config:        BulkheadConfig{MaxConcurrent: 1},
+			ctx:           func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			expectedError: ErrBulkheadFull,
+		},
+		{
+			name:          "Rejected after the queue timeout",
+			config:        BulkheadConfig{MaxConcurrent: 1, MaxQueue: 1, QueueTimeout: 10 * time.Millisecond},
+			ctx:           func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			expectedError: ErrBulkheadFull,
+			queueTimeout:  true,
+		},
+		{
+			name:   "Context done while queued",
+			config: BulkheadConfig{MaxConcurrent: 1, MaxQueue: 1},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+			expectedError: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			bulkhead := NewBulkhead(tt.config)
+			release, err := bulkhead.Acquire(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer release()
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			// Act
+			_, err = bulkhead.Acquire(ctx)
+
+			// Assert
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("error got %v, want %v", err, tt.expectedError)
+			}
+			var bulkheadErr *BulkheadError
+			if errors.As(err, &bulkheadErr) && bulkheadErr.QueueTimeout != tt.queueTimeout {
+				t.Errorf("queue timeout got %v, want %v", bulkheadErr.QueueTimeout, tt.queueTimeout)
+			}
+			if got := bulkhead.InFlight(); got != 1 {
+				t.Errorf("in flight got %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestBulkhead_Queue(t *testing.T) {
+	// Arrange
+	bulkhead := NewBulkhead(BulkheadConfig{MaxConcurrent: 1, MaxQueue: 2})
+	release, _ := bulkhead.Acquire(context.Background())
+	order := make(chan int, 2)
+	for index := range 2 {
+		go func() {
+			next, err := bulkhead.Acquire(context.Background())
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			order <- index
+			next()
+		}()
+		// Let the waiter queue before the next one
+		for {
+			bulkhead.mu.Lock()
+			queued := len(bulkhead.waiters)
+			bulkhead.mu.Unlock()
+			if queued == index+1 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// Act
+	release()
+	release() // a second call does nothing
+	first, second := <-order, <-order
+
+	// Assert
+	if first != 0 || second != 1 {
+		t.Errorf("order got %d, %d, want 0, 1", first, second)
+	}
+	if got := bulkhead.InFlight(); got != 0 {
+		t.Errorf("in flight got %d, want 0", got)
+	}
+}
+
+func TestBulkhead_Adaptive(t *testing.T) {
+	tests := []struct {
+		name     string
+		outcomes func(b *Bulkhead, start time.Time)
+		expected uint
+	}{
+		{
+			name: "A 5xx response halves the limit",
+			outcomes: func(b *Bulkhead, start time.Time) {
+				b.Record(start, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+			},
+			expected: 4,
+		},
+		{
+			name: "A slow response halves the limit",
+			outcomes: func(b *Bulkhead, start time.Time) {
+				b.Record(start.Add(-time.Second), &http.Response{StatusCode: http.StatusOK}, nil)
+			},
+			expected: 4,
+		},
+		{
+			name: "Concurrent failures halve the limit once",
+			outcomes: func(b *Bulkhead, start time.Time) {
+				b.Record(start, nil, errors.New("connection reset"))
+				b.Record(start.Add(-time.Millisecond), nil, errors.New("connection reset"))
+			},
+			expected: 4,
+		},
+		{
+			name: "Successes raise the limit additively",
+			outcomes: func(b *Bulkhead, start time.Time) {
+				b.Record(start, nil, errors.New("connection reset"))
+				for range 5 {
+					b.Record(start.Add(time.Millisecond), &http.Response{StatusCode: http.StatusOK}, nil)
+				}
+			},
+			expected: 5,
+		},
+		{
+			name: "The limit stays within bounds",
+			outcomes: func(b *Bulkhead, start time.Time) {
+				for offset := range 10 {
+					b.Record(start.Add(time.Duration(offset)), &http.Response{StatusCode: http.StatusBadGateway}, nil)
+				}
+			},
+			expected: 2,
+		},
+		{
+			name: "Cancellations are ignored",
+			outcomes: func(b *Bulkhead, start time.Time) {
+				b.Record(start, nil, context.Canceled)
+			},
+			expected: 8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			now := time.Unix(1_700_000_000, 0)
+			bulkhead := NewBulkhead(BulkheadConfig{
+				MaxConcurrent:    8,
+				MinConcurrent:    2,
+				Adaptive:         true,
+				LatencyThreshold: 500 * time.Millisecond,
+			})
+			bulkhead.now = func() time.Time { return now }
+
+			// Act
+			tt.outcomes(bulkhead, now)
+
+			// Assert
+			if got := bulkhead.Limit(); got != tt.expected {
+				t.Errorf("limit got %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBulkheadError_Error(t *testing.T) {
+	tests := []struct {
+		err      *BulkheadError
+		expected string
+	}{
+		{err: &BulkheadError{Limit: 5}, expected: "bulkhead is full: queue full (limit 5)"},
+		{err: &BulkheadError{Limit: 5, QueueTimeout: true}, expected: "bulkhead is full: no slot within the queue timeout (limit 5)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package fastshot
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/opus-domini/fast-shot/constant"
+)
+
+// ErrCircuitOpen is returned when a request is rejected without being sent because the circuit
+// of its base URL is open.
+var ErrCircuitOpen = errors.New(constant.ErrMsgCircuitOpen)
+
+// Default values of CircuitBreakerConfig.
+const (
+	defaultCircuitConsecutiveFailures = 5
+	defaultCircuitMinRequests         = 10
+	defaultCircuitWindow              = 10 * time.Second
+	defaultCircuitCooldown            = 30 * time.Second
+	defaultCircuitHalfOpenRequests    = 1
+)
+
+// Circuit states.
+const (
+	// CircuitClosed lets every request through while failures are counted.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every request until the cooldown is over.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through to decide whether the
+	// circuit closes again or opens for another cooldown.
+	CircuitHalfOpen
+)
+
+type (
+	// CircuitState is the state of the circuit of a base URL.
+	CircuitState int
+
+	// CircuitBreakerConfig configures a CircuitBreaker. Zero values fall back to the defaults
+	// documented on each field.
+	CircuitBreakerConfig struct {
+		// ConsecutiveFailures opens the circuit after this many failures in a row. Defaults to 5
+		// when FailureRate is not set either, and is disabled otherwise.
+		ConsecutiveFailures uint
+		// FailureRate opens the circuit when the share of failures within the window reaches
+		// it, e.g. 0.5 for 50%. Disabled by default.
+		FailureRate float64
+		// MinRequests is the number of requests within the window below which FailureRate is
+		// not evaluated. Defaults to 10.
+		MinRequests uint
+		// Window is the rolling window over which FailureRate is computed. Defaults to 10 seconds.
+		Window time.Duration
+		// Cooldown is how long the circuit stays open before letting probes through. Defaults to
+		// 30 seconds.
+		Cooldown time.Duration
+		// HalfOpenRequests is both the number of concurrent probes allowed while half-open and the
+		// number of successful probes that close the circuit. Defaults to 1.
+		HalfOpenRequests uint
+		// IsFailure reports whether an outcome counts as a failure. Defaults to transport errors
+		// other than cancellations, and 5xx responses. Errors that are not failures, such as
+		// cancellations, count neither as failures nor as successes.
+		IsFailure func(response *http.Response, err error) bool
+		// OnStateChange, if set, is called when the circuit of a base URL changes state.
+		OnStateChange func(baseURL string, from, to CircuitState)
+	}
+
+	// CircuitBreaker stops sending requests to a base URL that keeps failing, so callers fail fast
+	// with ErrCircuitOpen instead of waiting on timeouts and retries. Each base URL has its own
+	// circuit. It is safe for concurrent use by every request of a client.
+	CircuitBreaker struct {
+		config   CircuitBreakerConfig
+		mu       sync.Mutex
+		circuits map[string]*circuit
+		now      func() time.Time
+	}
+
+	// circuit tracks the state and recent outcomes of a base URL.
+	circuit struct {
+		state       CircuitState
+		generation  uint64
+		openedAt    time.Time
+		consecutive uint
+		probes      uint
+		successes   uint
+		buckets     []circuitBucket
+	}
+
+	// circuitBucket accounts the outcomes of one second of the window.
+	circuitBucket struct {
+		second   int64
+		requests uint
+		failures uint
+	}
+
+	// circuitTransition is a state change reported to OnStateChange once the lock is released.
+	circuitTransition struct {
+		baseURL  string
+		from, to CircuitState
+	}
+)
+
+// String returns the name of the circuit state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// NewCircuitBreaker creates a CircuitBreaker with the given configuration.
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.ConsecutiveFailures == 0 && config.FailureRate <= 0 {
+		config.ConsecutiveFailures = defaultCircuitConsecutiveFailures
+	}
+	if config.MinRequests == 0 {
+		config.MinRequests = defaultCircuitMinRequests
+	}
+	if config.Window <= 0 {
+		config.Window = defaultCircuitWindow
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = defaultCircuitCooldown
+	}
+	if config.HalfOpenRequests == 0 {
+		config.HalfOpenRequests = defaultCircuitHalfOpenRequests
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = isOutlierFailure
+	}
+	return &CircuitBreaker{
+		config:   config,
+		circuits: make(map[string]*circuit),
+		now:      time.Now,
+	}
+}
+
+// Config returns the effective configuration.
+func (b *CircuitBreaker) Config() CircuitBreakerConfig {
+	return b.config
+}
+
+// State returns the state of the circuit of the base URL. An open circuit whose cooldown is
+// over is reported as half-open.
+func (b *CircuitBreaker) State(baseURL string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[baseURL]
+	if !ok {
+		return CircuitClosed
+	}
+	if c.state == CircuitOpen && b.now().Sub(c.openedAt) >= b.config.Cooldown {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// Allow reports whether a request to the base URL may be sent. It returns ErrCircuitOpen when
+// the circuit is open, or half-open with every probe slot taken. Otherwise, it returns the
+// function that records the outcome of the request, which must be called exactly once. Calling
+// it with a nil response and a nil error releases the request without counting an outcome.
+func (b *CircuitBreaker) Allow(baseURL string) (func(response *http.Response, err error), error) {
+	var transitions []circuitTransition
+	defer func() { b.notify(transitions) }()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[baseURL]
+	if !ok {
+		c = &circuit{buckets: make([]circuitBucket, b.windowSeconds())}
+		b.circuits[baseURL] = c
+	}
+	if c.state == CircuitOpen {
+		if b.now().Sub(c.openedAt) < b.config.Cooldown {
+			return nil, ErrCircuitOpen
+		}
+		transitions = append(transitions, b.transition(baseURL, c, CircuitHalfOpen))
+	}
+
+	probe := c.state == CircuitHalfOpen
+	if probe {
+		if c.probes >= b.config.HalfOpenRequests {
+			return nil, ErrCircuitOpen
+		}
+		c.probes++
+	}
+
+	generation := c.generation
+	var once sync.Once
+	return func(response *http.Response, err error) {
+		once.Do(func() { b.record(baseURL, generation, probe, response, err) })
+	}, nil
+}
+
+// record accounts the outcome of a request allowed in the given generation of the circuit.
+// Outcomes of requests allowed before the last state change are ignored.
+func (b *CircuitBreaker) record(baseURL string, generation uint64, probe bool, response *http.Response, err error) {
+	var transitions []circuitTransition
+	defer func() { b.notify(transitions) }()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuits[baseURL]
+	if c.generation != generation {
+		return
+	}
+	if probe {
+		c.probes--
+	}
+	if response == nil && err == nil {
+		return
+	}
+
+	failure := b.config.IsFailure(response, err)
+	if err != nil && !failure {
+		return
+	}
+	if c.state == CircuitHalfOpen {
+		switch {
+		case failure:
+			transitions = append(transitions, b.transition(baseURL, c, CircuitOpen))
+		case c.successes+1 >= b.config.HalfOpenRequests:
+			transitions = append(transitions, b.transition(baseURL, c, CircuitClosed))
+		default:
+			c.successes++
+		}
+		return
+	}
+
+	bucket := b.bucket(c)
+	bucket.requests++
+	if failure {
+		bucket.failures++
+		c.consecutive++
+	} else {
+		c.consecutive = 0
+	}
+	if b.tripped(c) {
+		transitions = append(transitions, b.transition(baseURL, c, CircuitOpen))
+	}
+}
+
+// tripped reports whether the closed circuit must open. The caller must hold the lock.
+func (b *CircuitBreaker) tripped(c *circuit) bool {
+	if b.config.ConsecutiveFailures > 0 && c.consecutive >= b.config.ConsecutiveFailures {
+		return true
+	}
+	if b.config.FailureRate <= 0 {
+		return false
+	}
+
+	second := b.now().Unix()
+	var requests, failures uint
+	for _, bucket := range c.buckets {
+		if second-bucket.second < int64(len(c.buckets)) {
+			requests += bucket.requests
+			failures += bucket.failures
+		}
+	}
+	return requests >= b.config.MinRequests && float64(failures) >= b.config.FailureRate*float64(requests)
+}
+
+// transition moves the circuit to the given state and resets its counters. The caller must hold the lock.
+func (b *CircuitBreaker) transition(baseURL string, c *circuit, to CircuitState) circuitTransition {
+	from := c.state
+	c.state = to
+	c.generation++
+	c.consecutive, c.probes, c.successes = 0, 0, 0
+	clear(c.buckets)
+	if to == CircuitOpen {
+		c.openedAt = b.now()
+	}
+	return circuitTransition{baseURL: baseURL, from: from, to: to}
+}
+
+// notify reports the state changes to OnStateChange, if set.
+func (b *CircuitBreaker) notify(transitions []circuitTransition) {
+	if b.config.OnStateChange == nil {
+		return
+	}
+	for _, transition := range transitions {
+		b.config.OnStateChange(transition.baseURL, transition.from, transition.to)
+	}
+}
+
+// bucket returns the bucket of the circuit for the current second, resetting it when it belongs
+// to an old window. The caller must hold the lock.
+func (b *CircuitBreaker) bucket(c *circuit) *circuitBucket {
+	second := b.now().Unix()
+	bucket := &c.buckets[int(second%int64(len(c.buckets)))]
+	if bucket.second != second {
+		*bucket = circuitBucket{second: second}
+	}
+	return bucket
+}
+
+// windowSeconds returns the number of one-second buckets of the window.
+func (b *CircuitBreaker) windowSeconds() int {
+	return max(int((b.config.Window+time.Second-1)/time.Second), 1)
+}
@@ -0,0 +1,187 @@
+package fastshot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_Trip(t *testing.T) {
+	failure := &http.Response{StatusCode: http.StatusInternalServerError}
+	success := &http.Response{StatusCode: http.StatusOK}
+
+	tests := []struct {
+		name     string
+		config   CircuitBreakerConfig
+		outcomes []*http.Response
+		expected CircuitState
+	}{
+		{
+			name:     "Consecutive failures open the circuit",
+			config:   CircuitBreakerConfig{ConsecutiveFailures: 3},
+			outcomes: []*http.Response{failure, failure, failure},
+			expected: CircuitOpen,
+		},
+		{
+			name:     "A success resets consecutive failures",
+			config:   CircuitBreakerConfig{ConsecutiveFailures: 3},
+			outcomes: []*http.Response{failure, failure, success, failure, failure},
+			expected: CircuitClosed,
+		},
+		{
+			name:     "Failure rate opens the circuit",
+			config:   CircuitBreakerConfig{FailureRate: 0.5, MinRequests: 4},
+			outcomes: []*http.Response{failure, success, failure, success},
+			expected: CircuitOpen,
+		},
+		{
+			name:     "Failure rate needs the minimum requests",
+			config:   CircuitBreakerConfig{FailureRate: 0.5, MinRequests: 4},
+			outcomes: []*http.Response{failure, failure, failure},
+			expected: CircuitClosed,
+		},
+		{
+			name:     "Failure rate below the threshold",
+			config:   CircuitBreakerConfig{FailureRate: 0.5, MinRequests: 4},
+			outcomes: []*http.Response{failure, success, success, success, failure},
+			expected: CircuitClosed,
+		},
+		{
+			name:     "Client errors are not failures",
+			config:   CircuitBreakerConfig{ConsecutiveFailures: 1},
+			outcomes: []*http.Response{{StatusCode: http.StatusNotFound}},
+			expected: CircuitClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			breaker := NewCircuitBreaker(tt.config)
+
+			// Act
+			for _, outcome := range tt.outcomes {
+				record, err := breaker.Allow("https://a.com")
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				record(outcome, nil)
+			}
+
+			// Assert
+			if got := breaker.State("https://a.com"); got != tt.expected {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+			if got := breaker.State("https://b.com"); got != CircuitClosed {
+				t.Errorf("other base URL got %v, want %v", got, CircuitClosed)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	tests := []struct {
+		name     string
+		probe    func(record func(*http.Response, error))
+		expected CircuitState
+	}{
+		{
+			name:     "Successful probes close the circuit",
+			probe:    func(record func(*http.Response, error)) { record(&http.Response{StatusCode: http.StatusOK}, nil) },
+			expected: CircuitClosed,
+		},
+		{
+			name:     "A failed probe opens the circuit again",
+			probe:    func(record func(*http.Response, error)) { record(nil, errors.New("connection refused")) },
+			expected: CircuitOpen,
+		},
+		{
+			name:     "A canceled probe releases its slot",
+			probe:    func(record func(*http.Response, error)) { record(nil, context.Canceled) },
+			expected: CircuitHalfOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			now := time.Unix(1_700_000_000, 0)
+			var transitions []CircuitState
+			breaker := NewCircuitBreaker(CircuitBreakerConfig{
+				ConsecutiveFailures: 1,
+				Cooldown:            time.Minute,
+				HalfOpenRequests:    2,
+				OnStateChange: func(_ string, _, to CircuitState) {
+					transitions = append(transitions, to)
+				},
+			})
+			breaker.now = func() time.Time { return now }
+			record, _ := breaker.Allow("https://a.com")
+			record(nil, errors.New("connection refused"))
+
+			// Act
+			_, errCooldown := breaker.Allow("https://a.com")
+			now = now.Add(time.Minute)
+			first, errFirst := breaker.Allow("https://a.com")
+			second, errSecond := breaker.Allow("https://a.com")
+			_, errThird := breaker.Allow("https://a.com")
+			tt.probe(first)
+			tt.probe(second)
+
+			// Assert
+			if !errors.Is(errCooldown, ErrCircuitOpen) {
+				t.Errorf("cooldown error got %v, want %v", errCooldown, ErrCircuitOpen)
+			}
+			if errFirst != nil || errSecond != nil {
+				t.Fatalf("probe errors got %v and %v, want nil", errFirst, errSecond)
+			}
+			if !errors.Is(errThird, ErrCircuitOpen) {
+				t.Errorf("extra probe error got %v, want %v", errThird, ErrCircuitOpen)
+			}
+			if got := breaker.State("https://a.com"); got != tt.expected {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+			if got := transitions[len(transitions)-1]; tt.expected != CircuitHalfOpen && got != tt.expected {
+				t.Errorf("last transition got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_StaleOutcome(t *testing.T) {
+	// Arrange
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{ConsecutiveFailures: 1, Cooldown: time.Minute})
+	slow, _ := breaker.Allow("https://a.com")
+	failed, _ := breaker.Allow("https://a.com")
+	failed(nil, errors.New("connection refused"))
+
+	// Act
+	slow(&http.Response{StatusCode: http.StatusOK}, nil)
+
+	// Assert
+	if got := breaker.State("https://a.com"); got != CircuitOpen {
+		t.Errorf("got %v, want %v", got, CircuitOpen)
+	}
+}
+
+func TestCircuitState_String(t *testing.T) {
+	tests := []struct {
+		state    CircuitState
+		expected string
+	}{
+		{CircuitClosed, "closed"},
+		{CircuitOpen, "open"},
+		{CircuitHalfOpen, "half-open"},
+		{CircuitState(42), "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := tt.state.String(); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
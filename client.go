@@ -19,6 +19,22 @@ func NewClientLoadBalancer(baseURLs []string) *ClientBuilder {
 	}
 }
 
+// NewClientWeightedLoadBalancer initializes a new ClientBuilder with a given weighted endpoints.
+// Traffic is split between the endpoints in proportion to their weights.
+func NewClientWeightedLoadBalancer(endpoints []Endpoint) *ClientBuilder {
+	return &ClientBuilder{
+		client: newWeightedClientConfigBase(endpoints),
+	}
+}
+
+// NewClientLoadBalancerFromEndpoints initializes a new ClientBuilder whose base URLs come from an
+// Endpoints source and follow its updates.
+func NewClientLoadBalancerFromEndpoints(source Endpoints) *ClientBuilder {
+	return &ClientBuilder{
+		client: newEndpointsClientConfigBase(source),
+	}
+}
+
 // DefaultClient initializes a new default ClientConfig with a given baseURL.
 func DefaultClient(baseURL string) ClientHttpMethods {
 	return NewClient(baseURL).Build()
@@ -29,6 +45,11 @@ func DefaultClientLoadBalancer(baseURLs []string) ClientHttpMethods {
 	return NewClientLoadBalancer(baseURLs).Build()
 }
 
+// DefaultClientWeightedLoadBalancer initializes a new default ClientConfig with a given weighted endpoints.
+func DefaultClientWeightedLoadBalancer(endpoints []Endpoint) ClientHttpMethods {
+	return NewClientWeightedLoadBalancer(endpoints).Build()
+}
+
 // Build finalizes the ClientBuilder configurations and returns a new ClientConfig.
 func (b *ClientBuilder) Build() ClientHttpMethods {
 	return b.client
@@ -0,0 +1,28 @@
+package fastshot
+
+// BuilderBulkhead is the interface that wraps the basic methods for configuring the client bulkhead.
+var _ BuilderBulkhead[ClientBuilder] = (*ClientBulkheadBuilder)(nil)
+
+// ClientBulkheadBuilder serves as the main entry point for configuring the client bulkhead.
+type ClientBulkheadBuilder struct {
+	parentBuilder *ClientBuilder
+}
+
+// Bulkhead returns a new ClientBulkheadBuilder for configuring the concurrency limit shared by every request.
+func (b *ClientBuilder) Bulkhead() *ClientBulkheadBuilder {
+	return &ClientBulkheadBuilder{parentBuilder: b}
+}
+
+// Enable creates a bulkhead with the given configuration. Requests over the limit wait in the
+// queue, or fail with a BulkheadError without being sent.
+func (b *ClientBulkheadBuilder) Enable(config BulkheadConfig) *ClientBuilder {
+	return b.SetCustom(NewBulkhead(config))
+}
+
+// SetCustom sets a bulkhead created with NewBulkhead, e.g. to share it between clients.
+func (b *ClientBulkheadBuilder) SetCustom(bulkhead *Bulkhead) *ClientBuilder {
+	if config, ok := b.parentBuilder.client.(ConfigBulkhead); ok {
+		config.SetBulkhead(bulkhead)
+	}
+	return b.parentBuilder
+}
@@ -0,0 +1,40 @@
+package fastshot
+
+import "testing"
+
+func TestClientBulkheadBuilder_Enable(t *testing.T) {
+	// Arrange
+	cb := NewClient("https://example.com")
+
+	// Act
+	result := cb.Bulkhead().Enable(BulkheadConfig{MaxConcurrent: 10, MaxQueue: 5})
+
+	// Assert
+	if result != cb {
+		t.Errorf("got different builder, want same")
+	}
+	bulkhead := cb.client.(*ClientConfigBase).Bulkhead()
+	if bulkhead == nil {
+		t.Fatal("bulkhead got nil, want non-nil")
+	}
+	if got := bulkhead.Limit(); got != 10 {
+		t.Errorf("limit got %d, want 10", got)
+	}
+	if got := bulkhead.Config().MaxQueue; got != 5 {
+		t.Errorf("max queue got %d, want 5", got)
+	}
+}
+
+func TestClientBulkheadBuilder_SetCustom(t *testing.T) {
+	// Arrange
+	bulkhead := NewBulkhead(BulkheadConfig{})
+	cb := NewClient("https://example.com")
+
+	// Act
+	cb.Bulkhead().SetCustom(bulkhead)
+
+	// Assert
+	if cb.client.(*ClientConfigBase).Bulkhead() != bulkhead {
+		t.Error("got different bulkhead, want the custom one")
+	}
+}
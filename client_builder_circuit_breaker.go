@@ -0,0 +1,28 @@
+package fastshot
+
+// BuilderCircuitBreaker is the interface that wraps the basic methods for configuring the client circuit breaker.
+var _ BuilderCircuitBreaker[ClientBuilder] = (*ClientCircuitBreakerBuilder)(nil)
+
+// ClientCircuitBreakerBuilder serves as the main entry point for configuring the client circuit breaker.
+type ClientCircuitBreakerBuilder struct {
+	parentBuilder *ClientBuilder
+}
+
+// CircuitBreaker returns a new ClientCircuitBreakerBuilder for configuring the circuit breaker shared by every request.
+func (b *ClientBuilder) CircuitBreaker() *ClientCircuitBreakerBuilder {
+	return &ClientCircuitBreakerBuilder{parentBuilder: b}
+}
+
+// Enable creates a circuit breaker with the given configuration. Each base URL has its own
+// circuit, and requests to an open circuit fail with ErrCircuitOpen without being sent.
+func (b *ClientCircuitBreakerBuilder) Enable(config CircuitBreakerConfig) *ClientBuilder {
+	return b.SetCustom(NewCircuitBreaker(config))
+}
+
+// SetCustom sets a circuit breaker created with NewCircuitBreaker, e.g. to share it between clients.
+func (b *ClientCircuitBreakerBuilder) SetCustom(breaker *CircuitBreaker) *ClientBuilder {
+	if config, ok := b.parentBuilder.client.(ConfigCircuitBreaker); ok {
+		config.SetCircuitBreaker(breaker)
+	}
+	return b.parentBuilder
+}
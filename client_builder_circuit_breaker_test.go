@@ -0,0 +1,44 @@
+package fastshot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClientCircuitBreakerBuilder_Enable(t *testing.T) {
+	// Arrange
+	cb := NewClient("https://example.com")
+
+	// Act
+	result := cb.CircuitBreaker().Enable(CircuitBreakerConfig{FailureRate: 0.5, Cooldown: time.Minute})
+
+	// Assert
+	if result != cb {
+		t.Errorf("got different builder, want same")
+	}
+	breaker := cb.client.(*ClientConfigBase).CircuitBreaker()
+	if breaker == nil {
+		t.Fatal("circuit breaker got nil, want non-nil")
+	}
+	config := breaker.Config()
+	if config.FailureRate != 0.5 || config.Cooldown != time.Minute || config.ConsecutiveFailures != 0 {
+		t.Errorf("got failure rate %v, cooldown %v and consecutive failures %d, want 0.5, 1m0s and 0",
+			config.FailureRate, config.Cooldown, config.ConsecutiveFailures)
+	}
+}
+
+func TestClientCircuitBreakerBuilder_SetCustom(t *testing.T) {
+	// Arrange
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{})
+	first := NewClient("https://a.com")
+	second := NewClient("https://b.com")
+
+	// Act
+	first.CircuitBreaker().SetCustom(breaker)
+	second.CircuitBreaker().SetCustom(breaker)
+
+	// Assert
+	if first.client.(*ClientConfigBase).CircuitBreaker() != breaker || second.client.(*ClientConfigBase).CircuitBreaker() != breaker {
+		t.Error("got different circuit breakers, want the shared one")
+	}
+}
@@ -0,0 +1,29 @@
+package fastshot
+
+// BuilderCoalescing is the interface that wraps the basic methods for configuring request coalescing.
+var _ BuilderCoalescing[ClientBuilder] = (*ClientCoalescingBuilder)(nil)
+
+// ClientCoalescingBuilder serves as the main entry point for configuring request coalescing.
+type ClientCoalescingBuilder struct {
+	parentBuilder *ClientBuilder
+}
+
+// Coalescing returns a new ClientCoalescingBuilder for merging concurrent identical requests.
+func (b *ClientBuilder) Coalescing() *ClientCoalescingBuilder {
+	return &ClientCoalescingBuilder{parentBuilder: b}
+}
+
+// Enable creates a coalescer with the given configuration. Concurrent identical GET and HEAD
+// requests then share the response of a single call.
+func (b *ClientCoalescingBuilder) Enable(config CoalescingConfig) *ClientBuilder {
+	return b.SetCustom(NewCoalescer(config))
+}
+
+// SetCustom sets a coalescer created with NewCoalescer, e.g. to share it between clients. Requests of
+// load-balanced clients are only merged with those of the same client.
+func (b *ClientCoalescingBuilder) SetCustom(coalescer *Coalescer) *ClientBuilder {
+	if config, ok := b.parentBuilder.client.(ConfigCoalescer); ok {
+		config.SetCoalescer(coalescer)
+	}
+	return b.parentBuilder
+}
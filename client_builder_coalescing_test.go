@@ -0,0 +1,37 @@
+package fastshot
+
+import "testing"
+
+func TestClientCoalescingBuilder_Enable(t *testing.T) {
+	// Arrange
+	cb := NewClient("https://example.com")
+
+	// Act
+	result := cb.Coalescing().Enable(CoalescingConfig{VaryHeaders: []string{"Authorization"}})
+
+	// Assert
+	if result != cb {
+		t.Errorf("got different builder, want same")
+	}
+	coalescer := cb.client.(*ClientConfigBase).Coalescer()
+	if coalescer == nil {
+		t.Fatal("coalescer got nil, want non-nil")
+	}
+	if got := coalescer.Config().VaryHeaders; len(got) != 1 || got[0] != "Authorization" {
+		t.Errorf("vary headers got %v, want [Authorization]", got)
+	}
+}
+
+func TestClientCoalescingBuilder_SetCustom(t *testing.T) {
+	// Arrange
+	coalescer := NewCoalescer(CoalescingConfig{})
+	cb := NewClient("https://example.com")
+
+	// Act
+	cb.Coalescing().SetCustom(coalescer)
+
+	// Assert
+	if cb.client.(*ClientConfigBase).Coalescer() != coalescer {
+		t.Error("got different coalescer, want the custom one")
+	}
+}
@@ -7,7 +7,7 @@ import (
 // BuilderHook is the interface that wraps the basic methods for setting request hooks.
 var _ BuilderHook[ClientBuilder] = (*ClientHookBuilder)(nil)
 
-// ClientHookBuilder allows for setting pre-request and post-response hooks at the client level.
+// ClientHookBuilder allows for setting request lifecycle hooks at the client level.
 type ClientHookBuilder struct {
 	parentBuilder *ClientBuilder
 }
@@ -28,3 +28,27 @@ func (b *ClientHookBuilder) OnAfterResponse(hook func(*http.Request, *http.Respo
 	b.parentBuilder.client.AddAfterResponseHook(hook)
 	return b.parentBuilder
 }
+
+// OnRetry adds a retry-scheduled hook to the client.
+func (b *ClientHookBuilder) OnRetry(hook func(*http.Request, RetryEvent)) *ClientBuilder {
+	if config, ok := b.parentBuilder.client.(ConfigLifecycleHooks); ok {
+		config.AddRetryHook(hook)
+	}
+	return b.parentBuilder
+}
+
+// OnError adds a transport-error hook to the client.
+func (b *ClientHookBuilder) OnError(hook func(*http.Request, error)) *ClientBuilder {
+	if config, ok := b.parentBuilder.client.(ConfigLifecycleHooks); ok {
+		config.AddErrorHook(hook)
+	}
+	return b.parentBuilder
+}
+
+// OnGiveUp adds a give-up hook to the client.
+func (b *ClientHookBuilder) OnGiveUp(hook func(*http.Request, error)) *ClientBuilder {
+	if config, ok := b.parentBuilder.client.(ConfigLifecycleHooks); ok {
+		config.AddGiveUpHook(hook)
+	}
+	return b.parentBuilder
+}
@@ -55,6 +55,26 @@ func TestClientHookBuilder(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Add retry, error and give-up hooks",
+			setup: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.
+					Hook().OnRetry(func(req *http.Request, event RetryEvent) {}).
+					Hook().OnError(func(req *http.Request, err error) {}).
+					Hook().OnGiveUp(func(req *http.Request, err error) {})
+			},
+			assertFunc: func(t *testing.T, cb *ClientBuilder) {
+				if got := len(cb.client.(*ClientConfigBase).RetryHooks()); got != 1 {
+					t.Errorf("retry hooks count got %d, want 1", got)
+				}
+				if got := len(cb.client.(*ClientConfigBase).ErrorHooks()); got != 1 {
+					t.Errorf("error hooks count got %d, want 1", got)
+				}
+				if got := len(cb.client.(*ClientConfigBase).GiveUpHooks()); got != 1 {
+					t.Errorf("give-up hooks count got %d, want 1", got)
+				}
+			},
+		},
 		{
 			name: "No hooks by default",
 			setup: func(cb *ClientBuilder) *ClientBuilder {
@@ -0,0 +1,89 @@
+package fastshot
+
+import (
+	"errors"
+
+	"github.com/opus-domini/fast-shot/constant"
+)
+
+// BuilderLoadBalancer is the interface that wraps the basic methods for configuring client load balancing.
+var _ BuilderLoadBalancer[ClientBuilder] = (*ClientLoadBalancerBuilder)(nil)
+
+// ClientLoadBalancerBuilder serves as the main entry point for configuring client load balancing.
+type ClientLoadBalancerBuilder struct {
+	parentBuilder *ClientBuilder
+}
+
+// LoadBalancer returns a new ClientLoadBalancerBuilder for configuring how requests are spread across base URLs.
+func (b *ClientBuilder) LoadBalancer() *ClientLoadBalancerBuilder {
+	return &ClientLoadBalancerBuilder{parentBuilder: b}
+}
+
+// WithStrategy sets the strategy that picks the base URL of each attempt.
+func (b *ClientLoadBalancerBuilder) WithStrategy(strategy LoadBalancingStrategy) *ClientBuilder {
+	if balanced := b.balancer(); balanced != nil && strategy != nil {
+		balanced.SetStrategy(strategy)
+	}
+	return b.parentBuilder
+}
+
+// WithHealthCheck attaches an active health checker that probes every base URL in the background
+// and takes the unhealthy ones out of rotation. Probes use the client's HTTP client. Call Close on
+// the checker to stop them.
+func (b *ClientLoadBalancerBuilder) WithHealthCheck(checker *HealthChecker) *ClientBuilder {
+	if balanced := b.balancer(); balanced != nil && checker != nil {
+		client := b.parentBuilder.client
+		checker.start(balanced, client.HttpClient)
+	}
+	return b.parentBuilder
+}
+
+// WithOutlierDetection ejects base URLs from rotation after consecutive 5xx responses or transport
+// errors, for an ejection time that grows with repeated ejections.
+func (b *ClientLoadBalancerBuilder) WithOutlierDetection(config OutlierDetectionConfig) *ClientBuilder {
+	if balanced := b.balancer(); balanced != nil {
+		balanced.SetOutlierDetector(NewOutlierDetector(config))
+	}
+	return b.parentBuilder
+}
+
+// WithHashKey sets how the hash key of each request is derived, e.g. from a header. Consistent
+// hashing strategies use the key to send requests with the same key to the same base URL.
+// A key set on the request with LoadBalancer().WithHashKey takes precedence.
+func (b *ClientLoadBalancerBuilder) WithHashKey(hashKey HashKeyFunc) *ClientBuilder {
+	if balanced := b.balancer(); balanced != nil {
+		balanced.SetHashKeyFunc(hashKey)
+	}
+	return b.parentBuilder
+}
+
+// WithPriorityGroups sets how base URLs are grouped by priority and zone. Traffic stays on the
+// highest-ranked group with enough available base URLs, such as the local zone, and fails over to
+// the next group otherwise.
+func (b *ClientLoadBalancerBuilder) WithPriorityGroups(config PriorityGroupsConfig) *ClientBuilder {
+	if balanced := b.balancer(); balanced != nil {
+		balanced.SetPriorityGroups(config)
+	}
+	return b.parentBuilder
+}
+
+// WithSlowStart ramps up the weight of base URLs that join the rotation, once added, marked healthy
+// again or back from an ejection, so they do not take a full share of traffic while cold.
+func (b *ClientLoadBalancerBuilder) WithSlowStart(config SlowStartConfig) *ClientBuilder {
+	if balanced := b.balancer(); balanced != nil {
+		balanced.SetSlowStart(config)
+	}
+	return b.parentBuilder
+}
+
+// balancer returns the BalancedBaseURL of the client, recording a validation error when the
+// client is not load balanced.
+func (b *ClientLoadBalancerBuilder) balancer() *BalancedBaseURL {
+	if config, ok := b.parentBuilder.client.(*ClientConfigBase); ok {
+		if balanced, ok := config.ConfigBaseURL.(*BalancedBaseURL); ok {
+			return balanced
+		}
+	}
+	b.parentBuilder.client.Validations().Add(errors.New(constant.ErrMsgLoadBalancerRequired))
+	return nil
+}
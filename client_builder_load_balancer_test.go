@@ -0,0 +1,196 @@
+package fastshot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClientLoadBalancerBuilder(t *testing.T) {
+	strategy := NewLeastOutstandingStrategy()
+
+	tests := []struct {
+		name             string
+		builder          *ClientBuilder
+		method           func(*ClientBuilder) *ClientBuilder
+		expectedStrategy func(LoadBalancingStrategy) bool
+		expectError      bool
+	}{
+		{
+			name:    "Weighted round robin by default",
+			builder: NewClientLoadBalancer([]string{"https://a.com", "https://b.com"}),
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb
+			},
+			expectedStrategy: func(s LoadBalancingStrategy) bool {
+				_, ok := s.(*WeightedRoundRobinStrategy)
+				return ok
+			},
+		},
+		{
+			name: "Weighted round robin for uneven weights",
+			builder: NewClientWeightedLoadBalancer([]Endpoint{
+				{BaseURL: "https://a.com", Weight: 3},
+				{BaseURL: "https://b.com", Weight: 1},
+			}),
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb
+			},
+			expectedStrategy: func(s LoadBalancingStrategy) bool {
+				_, ok := s.(*WeightedRoundRobinStrategy)
+				return ok
+			},
+		},
+		{
+			name:    "Set strategy",
+			builder: NewClientLoadBalancer([]string{"https://a.com", "https://b.com"}),
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.LoadBalancer().WithStrategy(strategy)
+			},
+			expectedStrategy: func(s LoadBalancingStrategy) bool {
+				return s == strategy
+			},
+		},
+		{
+			name:    "Nil strategy is ignored",
+			builder: NewClientLoadBalancer([]string{"https://a.com", "https://b.com"}),
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.LoadBalancer().WithStrategy(nil)
+			},
+			expectedStrategy: func(s LoadBalancingStrategy) bool {
+				_, ok := s.(*WeightedRoundRobinStrategy)
+				return ok
+			},
+		},
+		{
+			name:    "Single base URL client records a validation error",
+			builder: NewClient("https://a.com"),
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.LoadBalancer().WithStrategy(strategy)
+			},
+			expectError: true,
+		},
+		{
+			name:    "Health check on single base URL client records a validation error",
+			builder: NewClient("https://a.com"),
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.LoadBalancer().WithHealthCheck(NewHealthChecker(HealthCheckConfig{}))
+			},
+			expectError: true,
+		},
+		{
+			name:    "Outlier detection on single base URL client records a validation error",
+			builder: NewClient("https://a.com"),
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.LoadBalancer().WithOutlierDetection(OutlierDetectionConfig{})
+			},
+			expectError: true,
+		},
+		{
+			name:    "Hash key on single base URL client records a validation error",
+			builder: NewClient("https://a.com"),
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.LoadBalancer().WithHashKey(HashKeyFromHeader("X-Tenant-ID"))
+			},
+			expectError: true,
+		},
+		{
+			name:    "Priority groups on single base URL client records a validation error",
+			builder: NewClient("https://a.com"),
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.LoadBalancer().WithPriorityGroups(PriorityGroupsConfig{LocalZone: "eu-1"})
+			},
+			expectError: true,
+		},
+		{
+			name:    "Slow start on single base URL client records a validation error",
+			builder: NewClient("https://a.com"),
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.LoadBalancer().WithSlowStart(SlowStartConfig{})
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			cb := tt.method(tt.builder)
+
+			// Assert
+			if got := cb.client.Validations().Count() > 0; got != tt.expectError {
+				t.Fatalf("validation error got %v, want %v", got, tt.expectError)
+			}
+			if tt.expectError {
+				return
+			}
+			balanced := cb.client.(*ClientConfigBase).ConfigBaseURL.(*BalancedBaseURL)
+			if !tt.expectedStrategy(balanced.Strategy()) {
+				t.Errorf("unexpected strategy %T", balanced.Strategy())
+			}
+		})
+	}
+}
+
+func TestClientLoadBalancerBuilder_WithOutlierDetection(t *testing.T) {
+	// Arrange
+	builder := NewClientLoadBalancer([]string{"https://a.com", "https://b.com"})
+
+	// Act
+	builder.LoadBalancer().WithOutlierDetection(OutlierDetectionConfig{ConsecutiveFailures: 2})
+
+	// Assert
+	balanced := builder.client.(*ClientConfigBase).ConfigBaseURL.(*BalancedBaseURL)
+	if balanced.OutlierDetector() == nil {
+		t.Fatal("got nil outlier detector")
+	}
+	if got := balanced.OutlierDetector().Config().ConsecutiveFailures; got != 2 {
+		t.Errorf("consecutive failures got %d, want 2", got)
+	}
+}
+
+func TestClientLoadBalancerBuilder_WithHashKey(t *testing.T) {
+	// Arrange
+	builder := NewClientLoadBalancer([]string{"https://a.com", "https://b.com"})
+
+	// Act
+	builder.LoadBalancer().WithHashKey(HashKeyFromQuery("tenant"))
+
+	// Assert
+	config := builder.client.(*ClientConfigBase)
+	if config.HashKeyFunc() == nil {
+		t.Fatal("got nil hash key function")
+	}
+	if got := NewClient("https://a.com").client.(*ClientConfigBase).HashKeyFunc(); got != nil {
+		t.Error("got hash key function on single base URL client, want nil")
+	}
+}
+
+func TestClientLoadBalancerBuilder_WithPriorityGroups(t *testing.T) {
+	// Arrange
+	builder := NewClientLoadBalancer([]string{"https://a.com", "https://b.com"})
+	config := PriorityGroupsConfig{LocalZone: "eu-1", MinHealthyPercent: 50}
+
+	// Act
+	builder.LoadBalancer().WithPriorityGroups(config)
+
+	// Assert
+	balanced := builder.client.(*ClientConfigBase).ConfigBaseURL.(*BalancedBaseURL)
+	if got := balanced.PriorityGroups(); got != config {
+		t.Errorf("got %+v, want %+v", got, config)
+	}
+}
+
+func TestClientLoadBalancerBuilder_WithSlowStart(t *testing.T) {
+	// Arrange
+	builder := NewClientLoadBalancer([]string{"https://a.com", "https://b.com"})
+
+	// Act
+	builder.LoadBalancer().WithSlowStart(SlowStartConfig{Window: time.Minute})
+
+	// Assert
+	balanced := builder.client.(*ClientConfigBase).ConfigBaseURL.(*BalancedBaseURL)
+	want := SlowStartConfig{Window: time.Minute, MinWeightPercent: 10, Aggression: 1}
+	if got := balanced.SlowStart(); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
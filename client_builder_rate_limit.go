@@ -0,0 +1,84 @@
+package fastshot
+
+import (
+	"errors"
+
+	"github.com/opus-domini/fast-shot/constant"
+)
+
+// BuilderRateLimit is the interface that wraps the basic methods for configuring client-side rate limiting.
+var _ BuilderRateLimit[ClientBuilder] = (*ClientRateLimitBuilder)(nil)
+
+// ClientRateLimitBuilder serves as the main entry point for configuring client-side rate limiting.
+type ClientRateLimitBuilder struct {
+	parentBuilder *ClientBuilder
+}
+
+// RateLimit returns a new ClientRateLimitBuilder for configuring the rate limits applied to every request.
+func (b *ClientBuilder) RateLimit() *ClientRateLimitBuilder {
+	return &ClientRateLimitBuilder{parentBuilder: b}
+}
+
+// Set limits every request of the client to rate requests per second, with bursts of up to burst requests.
+func (b *ClientRateLimitBuilder) Set(rate float64, burst uint) *ClientBuilder {
+	if b.validRate(rate) {
+		b.limits().SetLimiter(NewRateLimiter(rate, burst))
+	}
+	return b.parentBuilder
+}
+
+// SetCustom limits every request of the client with a limiter created with NewRateLimiter, e.g. to
+// share a quota between clients.
+func (b *ClientRateLimitBuilder) SetCustom(limiter *RateLimiter) *ClientBuilder {
+	b.limits().SetLimiter(limiter)
+	return b.parentBuilder
+}
+
+// SetPerHost limits the requests to each host to rate requests per second, with bursts of up to
+// burst requests, e.g. to respect the quota of every backend of a load-balanced client.
+func (b *ClientRateLimitBuilder) SetPerHost(rate float64, burst uint) *ClientBuilder {
+	if b.validRate(rate) {
+		b.limits().SetPerHost(rate, burst)
+	}
+	return b.parentBuilder
+}
+
+// SetPathPrefix limits the requests whose path starts with the prefix to rate requests per second,
+// with bursts of up to burst requests. When several prefixes match, the longest one applies.
+func (b *ClientRateLimitBuilder) SetPathPrefix(prefix string, rate float64, burst uint) *ClientBuilder {
+	if b.validRate(rate) {
+		b.limits().SetPathPrefix(prefix, NewRateLimiter(rate, burst))
+	}
+	return b.parentBuilder
+}
+
+// WithHeaderAdaptation lowers the rate to what the RateLimit-Policy, RateLimit-Remaining and
+// X-RateLimit-Remaining response headers allow, and pauses until the reset once the server
+// reports no remaining requests. The configured rate stays the upper bound, and responses without
+// such headers let the rate climb back to it.
+func (b *ClientRateLimitBuilder) WithHeaderAdaptation() *ClientBuilder {
+	b.limits().SetAdaptive(true)
+	return b.parentBuilder
+}
+
+// limits returns the rate limits of the client, creating them on first use. Clients without rate
+// limiting get detached rate limits, so the options are ignored.
+func (b *ClientRateLimitBuilder) limits() *RateLimits {
+	config, ok := b.parentBuilder.client.(ConfigRateLimits)
+	if !ok {
+		return NewRateLimits()
+	}
+	if config.RateLimits() == nil {
+		config.SetRateLimits(NewRateLimits())
+	}
+	return config.RateLimits()
+}
+
+// validRate reports whether the rate is positive, recording a validation error otherwise.
+func (b *ClientRateLimitBuilder) validRate(rate float64) bool {
+	if rate > 0 {
+		return true
+	}
+	b.parentBuilder.client.Validations().Add(errors.New(constant.ErrMsgInvalidRateLimit))
+	return false
+}
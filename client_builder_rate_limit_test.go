@@ -0,0 +1,73 @@
+package fastshot
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestClientRateLimitBuilder(t *testing.T) {
+	shared := NewRateLimiter(10, 1)
+
+	tests := []struct {
+		name          string
+		method        func(*ClientBuilder) *ClientBuilder
+		expectError   bool
+		expectedCount int
+	}{
+		{
+			name: "Client-wide limit",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.RateLimit().Set(50, 10)
+			},
+			expectedCount: 1,
+		},
+		{
+			name: "Shared limiter",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.RateLimit().SetCustom(shared)
+			},
+			expectedCount: 1,
+		},
+		{
+			name: "Per host and path prefix limits",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.RateLimit().SetPerHost(20, 5).
+					RateLimit().SetPathPrefix("/search", 1, 1).
+					RateLimit().WithHeaderAdaptation()
+			},
+			expectedCount: 2,
+		},
+		{
+			name: "Non-positive rate records a validation error",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.RateLimit().Set(0, 1)
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cb := NewClient("https://example.com")
+			target, _ := url.Parse("https://example.com/search")
+
+			// Act
+			result := tt.method(cb)
+
+			// Assert
+			if result != cb {
+				t.Errorf("got different builder, want same")
+			}
+			if got := cb.client.Validations().Count() > 0; got != tt.expectError {
+				t.Fatalf("validation error got %v, want %v", got, tt.expectError)
+			}
+			if tt.expectError {
+				return
+			}
+			if got := len(cb.client.(*ClientConfigBase).RateLimits().limiters(target)); got != tt.expectedCount {
+				t.Errorf("limiters got %d, want %d", got, tt.expectedCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package fastshot
+
+import "time"
+
+// BuilderRequestRetry is the interface that wraps the basic methods for setting the client default retry policy.
+var _ BuilderRequestRetry[ClientBuilder] = (*ClientRetryBuilder)(nil)
+
+// ClientRetryBuilder serves as the main entry point for configuring the client default retry policy.
+type ClientRetryBuilder struct {
+	parentBuilder *ClientBuilder
+}
+
+// Retry returns a new ClientRetryBuilder for setting the retry policy inherited by every request.
+func (b *ClientBuilder) Retry() *ClientRetryBuilder {
+	return &ClientRetryBuilder{parentBuilder: b}
+}
+
+// SetConstantBackoff sets the retry interval and maximum attempts.
+func (b *ClientRetryBuilder) SetConstantBackoff(interval time.Duration, maxAttempts uint) *ClientBuilder {
+	b.retryConfig().setBackoffPreset(interval, maxAttempts, 1, JitterStrategyNone)
+	return b.parentBuilder
+}
+
+// SetConstantBackoffWithJitter sets the retry interval and maximum attempts.
+func (b *ClientRetryBuilder) SetConstantBackoffWithJitter(interval time.Duration, maxAttempts uint) *ClientBuilder {
+	b.retryConfig().setBackoffPreset(interval, maxAttempts, 1, JitterStrategyFull)
+	return b.parentBuilder
+}
+
+// SetExponentialBackoff sets the retry interval, maximum attempts, and backoff rate.
+func (b *ClientRetryBuilder) SetExponentialBackoff(interval time.Duration, maxAttempts uint, backoffRate float64) *ClientBuilder {
+	b.retryConfig().setBackoffPreset(interval, maxAttempts, backoffRate, JitterStrategyNone)
+	return b.parentBuilder
+}
+
+// SetExponentialBackoffWithJitter sets the retry interval, maximum attempts, and backoff rate.
+func (b *ClientRetryBuilder) SetExponentialBackoffWithJitter(interval time.Duration, maxAttempts uint, backoffRate float64) *ClientBuilder {
+	b.retryConfig().setBackoffPreset(interval, maxAttempts, backoffRate, JitterStrategyFull)
+	return b.parentBuilder
+}
+
+// SetBackoffStrategy sets a custom backoff strategy and maximum attempts.
+func (b *ClientRetryBuilder) SetBackoffStrategy(strategy BackoffStrategy, maxAttempts uint) *ClientBuilder {
+	config := b.retryConfig()
+	config.SetMaxAttempts(maxAttempts)
+	config.SetBackoffStrategy(strategy)
+	return b.parentBuilder
+}
+
+// WithRetryCondition sets the retry condition for the client.
+func (b *ClientRetryBuilder) WithRetryCondition(shouldRetry func(response *Response) bool) *ClientBuilder {
+	b.retryConfig().SetShouldRetry(shouldRetry)
+	return b.parentBuilder
+}
+
+// WithRetryPredicate sets an error-aware retry condition for the client. Unlike WithRetryCondition,
+// the predicate also decides whether transport errors are retried, and takes precedence when both are set.
+func (b *ClientRetryBuilder) WithRetryPredicate(predicate RetryPredicate) *ClientBuilder {
+	b.retryConfig().SetRetryPredicate(predicate)
+	return b.parentBuilder
+}
+
+// WithMaxDelay sets the maximum delay for the client.
+func (b *ClientRetryBuilder) WithMaxDelay(duration time.Duration) *ClientBuilder {
+	b.retryConfig().SetMaxDelay(duration)
+	return b.parentBuilder
+}
+
+// WithMaxElapsedTime sets the overall retry deadline, measured from the first attempt.
+// No further attempt is made once the next one could not start before the deadline.
+func (b *ClientRetryBuilder) WithMaxElapsedTime(duration time.Duration) *ClientBuilder {
+	b.retryConfig().SetMaxElapsedTime(duration)
+	return b.parentBuilder
+}
+
+// WithPerAttemptTimeout gives each attempt its own deadline, within the request context.
+// A hung attempt then fails on its own and the next attempt can still succeed.
+// The timeout also bounds reading the body of the returned response.
+func (b *ClientRetryBuilder) WithPerAttemptTimeout(duration time.Duration) *ClientBuilder {
+	b.retryConfig().SetPerAttemptTimeout(duration)
+	return b.parentBuilder
+}
+
+// WithRetryAfter makes the retry delay follow the Retry-After, RateLimit-Reset or X-RateLimit-Reset
+// header of the last response, when present. The delay is still capped by WithMaxDelay, or at
+// five minutes when no maximum delay is set.
+func (b *ClientRetryBuilder) WithRetryAfter() *ClientBuilder {
+	b.retryConfig().SetRespectRetryAfter(true)
+	return b.parentBuilder
+}
+
+// WithFailover makes retries of load-balanced clients skip the base URLs that already failed
+// for the same request, as long as other base URLs remain.
+func (b *ClientRetryBuilder) WithFailover() *ClientBuilder {
+	b.retryConfig().SetExcludeFailedBaseURLs(true)
+	return b.parentBuilder
+}
+
+// Disable turns off the client default retry policy. Requests can still enable their own.
+func (b *ClientRetryBuilder) Disable() *ClientBuilder {
+	b.retryConfig().SetMaxAttempts(0)
+	return b.parentBuilder
+}
+
+// WithBudget caps retries across all requests of the client to ratio (e.g. 0.2 for 20%) of the
+// requests made in the last ten seconds, plus minRetriesPerSecond. Once the budget is spent,
+// requests stop retrying and fail with ErrRetryBudgetExhausted.
+func (b *ClientRetryBuilder) WithBudget(ratio float64, minRetriesPerSecond uint) *ClientBuilder {
+	if config, ok := b.parentBuilder.client.(ConfigRetry); ok {
+		config.SetRetryBudget(NewRetryBudget(ratio, minRetriesPerSecond, defaultRetryBudgetWindow))
+	}
+	return b.parentBuilder
+}
+
+// retryConfig returns the client default retry policy. Clients without one get a detached
+// policy, so the options are ignored.
+func (b *ClientRetryBuilder) retryConfig() *RetryConfig {
+	if config, ok := b.parentBuilder.client.(ConfigRetry); ok {
+		return config.RetryConfig()
+	}
+	return newDefaultRetryConfig()
+}
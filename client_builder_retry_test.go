@@ -0,0 +1,238 @@
+package fastshot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClientRetryBuilder(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         func(*ClientBuilder) *ClientBuilder
+		expectedConfig func(*RetryConfig) bool
+	}{
+		{
+			name: "Retries disabled by default",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.MaxAttempts() == 0
+			},
+		},
+		{
+			name: "Set constant backoff",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.Retry().SetConstantBackoff(time.Second, 3)
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.Interval() == time.Second &&
+					rc.MaxAttempts() == 3 &&
+					rc.BackoffRate() == 1 &&
+					rc.JitterStrategy() == JitterStrategyNone
+			},
+		},
+		{
+			name: "Set constant backoff with jitter",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.Retry().SetConstantBackoffWithJitter(time.Second, 3)
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.Interval() == time.Second &&
+					rc.MaxAttempts() == 3 &&
+					rc.JitterStrategy() == JitterStrategyFull
+			},
+		},
+		{
+			name: "Set exponential backoff",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.Retry().SetExponentialBackoff(time.Second, 3, 2.0)
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.Interval() == time.Second &&
+					rc.MaxAttempts() == 3 &&
+					rc.BackoffRate() == 2.0 &&
+					rc.JitterStrategy() == JitterStrategyNone
+			},
+		},
+		{
+			name: "Set exponential backoff with jitter",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.Retry().SetExponentialBackoffWithJitter(time.Second, 3, 2.0)
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.Interval() == time.Second &&
+					rc.MaxAttempts() == 3 &&
+					rc.BackoffRate() == 2.0 &&
+					rc.JitterStrategy() == JitterStrategyFull
+			},
+		},
+		{
+			name: "Set backoff strategy",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.Retry().SetBackoffStrategy(NewLinearBackoff(time.Second, time.Second), 4)
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				_, ok := rc.BackoffStrategy().(*LinearBackoff)
+				return ok && rc.MaxAttempts() == 4
+			},
+		},
+		{
+			name: "Set retry condition",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.Retry().WithRetryCondition(func(response *Response) bool {
+					return response.Status().Is5xxServerError()
+				})
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.ShouldRetry() != nil
+			},
+		},
+		{
+			name: "Set retry predicate",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.Retry().WithRetryPredicate(RetryOnNetworkErrors)
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.RetryPredicate() != nil
+			},
+		},
+		{
+			name: "Set max delay",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.Retry().WithMaxDelay(5 * time.Second)
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.MaxDelay() != nil && *rc.MaxDelay() == 5*time.Second
+			},
+		},
+		{
+			name: "Set max elapsed time",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.Retry().WithMaxElapsedTime(10 * time.Second)
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.MaxElapsedTime() != nil && *rc.MaxElapsedTime() == 10*time.Second
+			},
+		},
+		{
+			name: "Set per-attempt timeout",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.Retry().WithPerAttemptTimeout(2 * time.Second)
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.PerAttemptTimeout() != nil && *rc.PerAttemptTimeout() == 2*time.Second
+			},
+		},
+		{
+			name: "Set retry after",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.Retry().WithRetryAfter()
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.RespectRetryAfter()
+			},
+		},
+		{
+			name: "Set failover",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.Retry().WithFailover()
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.ExcludeFailedBaseURLs()
+			},
+		},
+		{
+			name: "Disable",
+			method: func(cb *ClientBuilder) *ClientBuilder {
+				return cb.Retry().SetConstantBackoff(time.Second, 3).Retry().Disable()
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.MaxAttempts() == 0
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cb := NewClient("https://example.com")
+
+			// Act
+			result := tt.method(cb)
+
+			// Assert
+			if result != cb {
+				t.Errorf("got different builder, want same")
+			}
+			if !tt.expectedConfig(cb.client.(*ClientConfigBase).RetryConfig()) {
+				t.Errorf("expectedConfig returned false")
+			}
+		})
+	}
+}
+
+func TestClientRetryBuilder_Inheritance(t *testing.T) {
+	t.Run("Request inherits client policy", func(t *testing.T) {
+		// Arrange
+		client := NewClient("https://example.com").
+			Retry().SetExponentialBackoff(time.Second, 4, 3.0).
+			Retry().WithMaxDelay(10 * time.Second).
+			Build()
+
+		// Act
+		config := client.GET("/test").request.config.RetryConfig()
+
+		// Assert
+		if config.MaxAttempts() != 4 || config.BackoffRate() != 3.0 {
+			t.Errorf("got maxAttempts %d and backoffRate %v, want 4 and 3", config.MaxAttempts(), config.BackoffRate())
+		}
+		if config.MaxDelay() == nil || *config.MaxDelay() != 10*time.Second {
+			t.Errorf("max delay got %v, want %v", config.MaxDelay(), 10*time.Second)
+		}
+	})
+
+	t.Run("Request override does not leak into client", func(t *testing.T) {
+		// Arrange
+		cb := NewClient("https://example.com").
+			Retry().SetConstantBackoff(time.Second, 3).
+			Retry().WithMaxDelay(10 * time.Second)
+		client := cb.Build()
+
+		// Act
+		request := client.GET("/test").
+			Retry().WithMaxDelay(time.Second).
+			Retry().Disable()
+
+		// Assert
+		if got := request.request.config.RetryConfig().MaxAttempts(); got != 0 {
+			t.Errorf("request max attempts got %d, want 0", got)
+		}
+		if got := cb.client.(*ClientConfigBase).RetryConfig().MaxAttempts(); got != 3 {
+			t.Errorf("client max attempts got %d, want 3", got)
+		}
+		if got := *cb.client.(*ClientConfigBase).RetryConfig().MaxDelay(); got != 10*time.Second {
+			t.Errorf("client max delay got %v, want %v", got, 10*time.Second)
+		}
+	})
+}
+
+func TestClientRetryBuilder_WithBudget(t *testing.T) {
+	// Arrange
+	cb := NewClient("https://example.com")
+
+	// Act
+	result := cb.Retry().WithBudget(0.2, 5)
+
+	// Assert
+	if result != cb {
+		t.Errorf("got different builder, want same")
+	}
+	budget := cb.client.(*ClientConfigBase).RetryBudget()
+	if budget == nil {
+		t.Fatal("retry budget got nil, want non-nil")
+	}
+	if budget.ratio != 0.2 || budget.minRetriesPerSecond != 5 {
+		t.Errorf("got ratio %v and minimum %d, want 0.2 and 5", budget.ratio, budget.minRetriesPerSecond)
+	}
+}
@@ -2,7 +2,6 @@ package fastshot
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 	"net/url"
 
@@ -10,14 +9,33 @@ import (
 	"github.com/opus-domini/fast-shot/constant/method"
 )
 
+var (
+	_ ClientConfig         = (*ClientConfigBase)(nil)
+	_ ConfigRetry          = (*ClientConfigBase)(nil)
+	_ ConfigLifecycleHooks = (*ClientConfigBase)(nil)
+	_ ConfigCircuitBreaker = (*ClientConfigBase)(nil)
+	_ ConfigRateLimits     = (*ClientConfigBase)(nil)
+	_ ConfigBulkhead       = (*ClientConfigBase)(nil)
+	_ ConfigCoalescer      = (*ClientConfigBase)(nil)
+)
+
 // ClientConfigBase serves as the main entry point for configuring HTTP clients.
 type ClientConfigBase struct {
 	httpClient    HttpClientComponent
 	httpHeader    HeaderWrapper
 	httpCookies   CookiesWrapper
 	validations   ValidationsWrapper
+	retryConfig   *RetryConfig
+	retryBudget   *RetryBudget
+	breaker       *CircuitBreaker
+	rateLimits    *RateLimits
+	bulkhead      *Bulkhead
+	coalescer     *Coalescer
 	beforeRequest []func(*http.Request) error
 	afterResponse []func(*http.Request, *http.Response)
+	onRetry       []func(*http.Request, RetryEvent)
+	onError       []func(*http.Request, error)
+	onGiveUp      []func(*http.Request, error)
 	ConfigBaseURL
 }
 
@@ -46,6 +64,61 @@ func (c *ClientConfigBase) Validations() ValidationsWrapper {
 	return c.validations
 }
 
+// RetryConfig for ClientConfigBase returns the default retry configuration inherited by every request.
+func (c *ClientConfigBase) RetryConfig() *RetryConfig {
+	return c.retryConfig
+}
+
+// RetryBudget for ClientConfigBase returns the retry budget shared by every request, if any.
+func (c *ClientConfigBase) RetryBudget() *RetryBudget {
+	return c.retryBudget
+}
+
+// SetRetryBudget for ClientConfigBase sets the retry budget shared by every request.
+func (c *ClientConfigBase) SetRetryBudget(budget *RetryBudget) {
+	c.retryBudget = budget
+}
+
+// CircuitBreaker for ClientConfigBase returns the circuit breaker shared by every request, if any.
+func (c *ClientConfigBase) CircuitBreaker() *CircuitBreaker {
+	return c.breaker
+}
+
+// SetCircuitBreaker for ClientConfigBase sets the circuit breaker shared by every request.
+func (c *ClientConfigBase) SetCircuitBreaker(breaker *CircuitBreaker) {
+	c.breaker = breaker
+}
+
+// RateLimits for ClientConfigBase returns the rate limits applied to every request, if any.
+func (c *ClientConfigBase) RateLimits() *RateLimits {
+	return c.rateLimits
+}
+
+// SetRateLimits for ClientConfigBase sets the rate limits applied to every request.
+func (c *ClientConfigBase) SetRateLimits(limits *RateLimits) {
+	c.rateLimits = limits
+}
+
+// Bulkhead for ClientConfigBase returns the bulkhead shared by every request, if any.
+func (c *ClientConfigBase) Bulkhead() *Bulkhead {
+	return c.bulkhead
+}
+
+// SetBulkhead for ClientConfigBase sets the bulkhead shared by every request.
+func (c *ClientConfigBase) SetBulkhead(bulkhead *Bulkhead) {
+	c.bulkhead = bulkhead
+}
+
+// Coalescer for ClientConfigBase returns the coalescer shared by every request, if any.
+func (c *ClientConfigBase) Coalescer() *Coalescer {
+	return c.coalescer
+}
+
+// SetCoalescer for ClientConfigBase sets the coalescer shared by every request.
+func (c *ClientConfigBase) SetCoalescer(coalescer *Coalescer) {
+	c.coalescer = coalescer
+}
+
 // BeforeRequestHooks returns the before-request hooks.
 func (c *ClientConfigBase) BeforeRequestHooks() []func(*http.Request) error {
 	return c.beforeRequest
@@ -67,6 +140,76 @@ func (c *ClientConfigBase) AddAfterResponseHook(hook func(*http.Request, *http.R
 	c.afterResponse = append(c.afterResponse, hook)
 }
 
+// SelectBaseURL for ClientConfigBase selects the base URL for an attempt. Clients with a single
+// base URL ignore the criteria.
+func (c *ClientConfigBase) SelectBaseURL(criteria BaseURLCriteria) *url.URL {
+	if selector, ok := c.ConfigBaseURL.(BaseURLSelector); ok {
+		return selector.SelectBaseURL(criteria)
+	}
+	return c.BaseURL()
+}
+
+// RequestStarted for ClientConfigBase tells a load-balanced base URL that a request to it started.
+// It returns nil for clients with a single base URL.
+func (c *ClientConfigBase) RequestStarted(baseURL *url.URL) func() {
+	if observer, ok := c.ConfigBaseURL.(BaseURLObserver); ok {
+		return observer.RequestStarted(baseURL)
+	}
+	return nil
+}
+
+// RecordOutcome for ClientConfigBase feeds the outcome of a request to a load-balanced base URL.
+func (c *ClientConfigBase) RecordOutcome(baseURL *url.URL, response *http.Response, err error) {
+	if observer, ok := c.ConfigBaseURL.(BaseURLObserver); ok {
+		observer.RecordOutcome(baseURL, response, err)
+	}
+}
+
+// HashKeyFunc for ClientConfigBase returns the function a load-balanced base URL uses to derive
+// the hash key of each request. It returns nil for clients with a single base URL.
+func (c *ClientConfigBase) HashKeyFunc() HashKeyFunc {
+	if provider, ok := c.ConfigBaseURL.(HashKeyProvider); ok {
+		return provider.HashKeyFunc()
+	}
+	return nil
+}
+
+// Balanced for ClientConfigBase reports whether requests are spread across several base URLs.
+func (c *ClientConfigBase) Balanced() bool {
+	balancer, ok := c.ConfigBaseURL.(BaseURLBalancer)
+	return ok && balancer.Balanced()
+}
+
+// RetryHooks returns the retry-scheduled hooks.
+func (c *ClientConfigBase) RetryHooks() []func(*http.Request, RetryEvent) {
+	return c.onRetry
+}
+
+// ErrorHooks returns the transport-error hooks.
+func (c *ClientConfigBase) ErrorHooks() []func(*http.Request, error) {
+	return c.onError
+}
+
+// GiveUpHooks returns the give-up hooks.
+func (c *ClientConfigBase) GiveUpHooks() []func(*http.Request, error) {
+	return c.onGiveUp
+}
+
+// AddRetryHook appends a retry-scheduled hook.
+func (c *ClientConfigBase) AddRetryHook(hook func(*http.Request, RetryEvent)) {
+	c.onRetry = append(c.onRetry, hook)
+}
+
+// AddErrorHook appends a transport-error hook.
+func (c *ClientConfigBase) AddErrorHook(hook func(*http.Request, error)) {
+	c.onError = append(c.onError, hook)
+}
+
+// AddGiveUpHook appends a give-up hook.
+func (c *ClientConfigBase) AddGiveUpHook(hook func(*http.Request, error)) {
+	c.onGiveUp = append(c.onGiveUp, hook)
+}
+
 // GET is a shortcut for NewRequest(c, method.GET, path).
 func (c *ClientConfigBase) GET(path string) *RequestBuilder {
 	return newRequest(c, method.GET, path)
@@ -130,30 +273,48 @@ func newClientConfigBase(baseURL string) *ClientConfigBase {
 		httpHeader:    newDefaultHttpHeader(),
 		httpCookies:   newDefaultHttpCookies(),
 		validations:   newDefaultValidations(validations),
+		retryConfig:   newDefaultRetryConfig(),
 		ConfigBaseURL: newDefaultBaseURL(parsedURL),
 	}
 }
 
 // newBalancedClientConfigBase initializes a new ClientConfigBase with a given baseURLs.
 func newBalancedClientConfigBase(baseURLs []string) *ClientConfigBase {
+	endpoints := make([]Endpoint, len(baseURLs))
+	for index, baseURL := range baseURLs {
+		endpoints[index] = Endpoint{BaseURL: baseURL}
+	}
+	return newWeightedClientConfigBase(endpoints)
+}
+
+// newWeightedClientConfigBase initializes a new ClientConfigBase with a given weighted endpoints.
+func newWeightedClientConfigBase(endpoints []Endpoint) *ClientConfigBase {
 	var validations []error
 
-	var parsedURLs []*url.URL
-	for index, baseURL := range baseURLs {
-		if baseURL == "" {
-			validations = append(validations, fmt.Errorf("base URL %d: %s", index, constant.ErrMsgEmptyBaseURL))
-			continue
-		}
-
-		parsedURL, err := url.Parse(baseURL)
-		if err != nil {
-			validations = append(validations, errors.Join(errors.New(constant.ErrMsgParseURL), err))
-		}
-		parsedURLs = append(parsedURLs, parsedURL)
+	backends, err := parseEndpoints(endpoints)
+	if err != nil {
+		validations = append(validations, err)
+	}
+
+	return &ClientConfigBase{
+		httpClient:    newDefaultHttpClient(),
+		httpHeader:    newDefaultHttpHeader(),
+		httpCookies:   newDefaultHttpCookies(),
+		validations:   newDefaultValidations(validations),
+		retryConfig:   newDefaultRetryConfig(),
+		ConfigBaseURL: newBackendsBalancedBaseURL(backends),
 	}
+}
+
+// newEndpointsClientConfigBase initializes a new ClientConfigBase with the base URLs of a given source.
+func newEndpointsClientConfigBase(source Endpoints) *ClientConfigBase {
+	var validations []error
 
-	if len(parsedURLs) == 0 {
+	balanced := newWeightedBalancedBaseURL(nil, nil)
+	if source == nil {
 		validations = append(validations, errors.New(constant.ErrMsgEmptyBaseURL))
+	} else {
+		source.Watch(balanced)
 	}
 
 	return &ClientConfigBase{
@@ -161,6 +322,7 @@ func newBalancedClientConfigBase(baseURLs []string) *ClientConfigBase {
 		httpHeader:    newDefaultHttpHeader(),
 		httpCookies:   newDefaultHttpCookies(),
 		validations:   newDefaultValidations(validations),
-		ConfigBaseURL: newBalancedBaseURL(parsedURLs),
+		retryConfig:   newDefaultRetryConfig(),
+		ConfigBaseURL: balanced,
 	}
 }
@@ -3,6 +3,7 @@ package fastshot
 import (
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	"github.com/opus-domini/fast-shot/constant/method"
@@ -62,6 +63,11 @@ func TestNewClientLoadBalancer(t *testing.T) {
 			baseURLs:    []string{"https://example1.com", ""},
 			expectError: true,
 		},
+		{
+			name:        "Relative URL",
+			baseURLs:    []string{"https://example1.com", "/api"},
+			expectError: true,
+		},
 		{
 			name:        "Empty URL List",
 			baseURLs:    []string{},
@@ -79,6 +85,103 @@ func TestNewClientLoadBalancer(t *testing.T) {
 	}
 }
 
+func TestNewClientLoadBalancer_DuplicateURLs(t *testing.T) {
+	// Act
+	clientBuilder := NewClientLoadBalancer([]string{"https://example1.com", "https://example2.com", "https://example1.com"})
+
+	// Assert
+	if count := clientBuilder.client.Validations().Count(); count != 0 {
+		t.Fatalf("got %d validation errors, want 0", count)
+	}
+	var got []string
+	for _, backend := range clientBuilder.client.(*ClientConfigBase).ConfigBaseURL.(*BalancedBaseURL).Backends() {
+		got = append(got, backend.URL.String())
+	}
+	if want := []string{"https://example1.com", "https://example2.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestNewClientWeightedLoadBalancer(t *testing.T) {
+	tests := []struct {
+		name        string
+		endpoints   []Endpoint
+		expectError bool
+	}{
+		{
+			name: "Successful Weighted Client Load Balancer Creation",
+			endpoints: []Endpoint{
+				{BaseURL: "https://example1.com", Weight: 95},
+				{BaseURL: "https://example2.com", Weight: 5},
+			},
+			expectError: false,
+		},
+		{
+			name: "Error Parsing URL",
+			endpoints: []Endpoint{
+				{BaseURL: "https://example1.com", Weight: 1},
+				{BaseURL: ":%^:", Weight: 1},
+			},
+			expectError: true,
+		},
+		{
+			name: "Empty URL",
+			endpoints: []Endpoint{
+				{BaseURL: "https://example1.com", Weight: 1},
+				{BaseURL: "", Weight: 1},
+			},
+			expectError: true,
+		},
+		{
+			name:        "Empty Endpoint List",
+			endpoints:   []Endpoint{},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientBuilder := NewClientWeightedLoadBalancer(tt.endpoints)
+			if (clientBuilder.client.Validations().Count() > 0) != tt.expectError {
+				t.Errorf("NewClientWeightedLoadBalancer() error = %v, expectError %v", clientBuilder.client.Validations().Count() > 0, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestNewClientLoadBalancerFromEndpoints(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      Endpoints
+		expectError bool
+	}{
+		{
+			name:        "Successful Client Load Balancer From Endpoints Creation",
+			source:      NewEndpointSet([]Endpoint{{BaseURL: "https://example1.com"}}),
+			expectError: false,
+		},
+		{
+			name:        "Empty Endpoint Set",
+			source:      NewEndpointSet(nil),
+			expectError: false,
+		},
+		{
+			name:        "Nil Endpoints Source",
+			source:      nil,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientBuilder := NewClientLoadBalancerFromEndpoints(tt.source)
+			if (clientBuilder.client.Validations().Count() > 0) != tt.expectError {
+				t.Errorf("NewClientLoadBalancerFromEndpoints() error = %v, expectError %v", clientBuilder.client.Validations().Count() > 0, tt.expectError)
+			}
+		})
+	}
+}
+
 func TestClientMethods(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		_, err := w.Write([]byte("OK"))
@@ -94,6 +197,7 @@ func TestClientMethods(t *testing.T) {
 	}{
 		{"clientDefault", DefaultClient(server.URL)},
 		{"clientLoadBalancer", DefaultClientLoadBalancer([]string{server.URL})},
+		{"clientWeightedLoadBalancer", DefaultClientWeightedLoadBalancer([]Endpoint{{BaseURL: server.URL, Weight: 2}})},
 	}
 
 	for _, client := range clients {
@@ -0,0 +1,163 @@
+package fastshot
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/opus-domini/fast-shot/constant/method"
+)
+
+type (
+	// CoalescingConfig configures a Coalescer.
+	CoalescingConfig struct {
+		// VaryHeaders are the request headers that tell identical requests apart besides the method
+		// and the full URL, e.g. Authorization or Accept. Requests that only differ by other
+		// headers share a response.
+		VaryHeaders []string
+	}
+
+	// Coalescer merges concurrent identical GET and HEAD requests into a single call: while a
+	// request is in flight, identical ones wait for its response instead of being sent. The
+	// response is buffered, so each caller reads its own copy of the body. It is safe for
+	// concurrent use by every request of a client, and can be shared between clients: requests
+	// of load-balanced clients are only merged with those of the same client and hash key.
+	Coalescer struct {
+		config CoalescingConfig
+		mu     sync.Mutex
+		calls  map[string]*coalescedCall
+	}
+
+	// coalescedCall is a request in flight and, once done, its buffered outcome.
+	coalescedCall struct {
+		done     chan struct{}
+		callers  int
+		cancel   context.CancelFunc
+		response *http.Response
+		body     []byte
+		err      error
+	}
+)
+
+// NewCoalescer creates a Coalescer with the given configuration.
+func NewCoalescer(config CoalescingConfig) *Coalescer {
+	return &Coalescer{
+		config: config,
+		calls:  make(map[string]*coalescedCall),
+	}
+}
+
+// Config returns the configuration of the coalescer.
+func (c *Coalescer) Config() CoalescingConfig {
+	return c.config
+}
+
+// coalescable reports whether requests with the given method can share a response.
+func (c *Coalescer) coalescable(m method.Type) bool {
+	return m == method.GET || m == method.HEAD
+}
+
+// key returns the key shared by identical requests: the method, the full URL, the scope that
+// tells apart the targets of requests with the same URL, if any, and the values of the vary
+// headers. The request carries the client and request headers and cookies, but not the headers
+// set by before-request hooks.
+func (c *Coalescer) key(req *http.Request, scope string) string {
+	var key strings.Builder
+	key.WriteString(req.Method)
+	key.WriteByte(' ')
+	key.WriteString(req.URL.String())
+	if scope != "" {
+		key.WriteString("\n@")
+		key.WriteString(scope)
+	}
+	for _, name := range c.config.VaryHeaders {
+		key.WriteByte('\n')
+		key.WriteString(http.CanonicalHeaderKey(name))
+		key.WriteByte(':')
+		key.WriteString(strings.Join(req.Header.Values(name), ","))
+	}
+	return key.String()
+}
+
+// do sends the request unless an identical one is in flight, in which case it waits for that
+// response, or until the context is done. The shared call runs on a context no caller owns, which
+// keeps the values of the context of the first caller and is canceled once every caller has left.
+// Every caller gets its own copy of the response.
+func (c *Coalescer) do(ctx context.Context, key string, send func(ctx context.Context) (*Response, error)) (*Response, error) {
+	c.mu.Lock()
+	call, ok := c.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &coalescedCall{done: make(chan struct{}), cancel: cancel}
+		c.calls[key] = call
+		go c.run(callCtx, key, call, send)
+	}
+	call.callers++
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		c.leave(key, call)
+		return call.result()
+	case <-ctx.Done():
+		c.leave(key, call)
+		return nil, ctx.Err()
+	}
+}
+
+// run sends the shared request and buffers its outcome.
+func (c *Coalescer) run(ctx context.Context, key string, call *coalescedCall, send func(ctx context.Context) (*Response, error)) {
+	defer func() {
+		c.mu.Lock()
+		c.forget(key, call)
+		c.mu.Unlock()
+		call.cancel()
+		close(call.done)
+	}()
+	response, err := send(ctx)
+	if err != nil {
+		call.err = err
+		return
+	}
+	// Buffer the body, closing it to release the connection
+	call.body, err = io.ReadAll(response.Raw().Body)
+	_ = response.Raw().Body.Close()
+	if err != nil {
+		call.err = err
+		return
+	}
+	call.response = response.Raw()
+}
+
+// leave removes a caller from the call, canceling the call once no caller is left. A canceled
+// call is forgotten right away, so later identical requests are not joined to it.
+func (c *Coalescer) leave(key string, call *coalescedCall) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	call.callers--
+	if call.callers == 0 {
+		c.forget(key, call)
+		call.cancel()
+	}
+}
+
+// forget removes the call from the calls in flight, unless a newer call took its key.
+func (c *Coalescer) forget(key string, call *coalescedCall) {
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+}
+
+// result returns a copy of the buffered response, with a body of its own.
+func (c *coalescedCall) result() (*Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	response := *c.response
+	response.Header = c.response.Header.Clone()
+	response.Body = io.NopCloser(bytes.NewReader(c.body))
+	return newResponse(&response), nil
+}
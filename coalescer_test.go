@@ -0,0 +1,289 @@
+package fastshot
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opus-domini/fast-shot/constant/method"
+)
+
+func TestCoalescer_key(t *testing.T) {
+	tests := []struct {
+		name        string
+		vary        []string
+		method      method.Type
+		header      http.Header
+		scope       string
+		otherMethod method.Type
+		otherHeader http.Header
+		otherScope  string
+		expected    bool
+	}{
+		{
+			name:        "Same method, URL and headers",
+			method:      method.GET,
+			header:      http.Header{"Accept": {"application/json"}},
+			otherMethod: method.GET,
+			otherHeader: http.Header{"Accept": {"application/json"}},
+			expected:    true,
+		},
+		{
+			name:        "Different method",
+			method:      method.GET,
+			otherMethod: method.HEAD,
+			expected:    false,
+		},
+		{
+			name:        "Different scope",
+			method:      method.GET,
+			scope:       `0xc000010000 "tenant-a"`,
+			otherMethod: method.GET,
+			otherScope:  `0xc000010000 "tenant-b"`,
+			expected:    false,
+		},
+		{
+			name:        "Different vary header",
+			vary:        []string{"authorization"},
+			method:      method.GET,
+			header:      http.Header{"Authorization": {"Bearer a"}},
+			otherMethod: method.GET,
+			otherHeader: http.Header{"Authorization": {"Bearer b"}},
+			expected:    false,
+		},
+		{
+			name:        "Different header not in vary",
+			vary:        []string{"Authorization"},
+			method:      method.GET,
+			header:      http.Header{"Authorization": {"Bearer a"}, "X-Trace": {"1"}},
+			otherMethod: method.GET,
+			otherHeader: http.Header{"Authorization": {"Bearer a"}, "X-Trace": {"2"}},
+			expected:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			coalescer := NewCoalescer(CoalescingConfig{VaryHeaders: tt.vary})
+
+			// Act
+			key := coalescer.key(newCoalescingRequest(tt.method, tt.header), tt.scope)
+			other := coalescer.key(newCoalescingRequest(tt.otherMethod, tt.otherHeader), tt.otherScope)
+
+			// Assert
+			if got := key == other; got != tt.expected {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCoalescer_coalescable(t *testing.T) {
+	// Arrange
+	coalescer := NewCoalescer(CoalescingConfig{})
+	expected := map[method.Type]bool{
+		method.GET:    true,
+		method.HEAD:   true,
+		method.POST:   false,
+		method.PUT:    false,
+		method.DELETE: false,
+	}
+
+	// Act & Assert
+	for m, want := range expected {
+		if got := coalescer.coalescable(m); got != want {
+			t.Errorf("%s got %v, want %v", m, got, want)
+		}
+	}
+}
+
+func TestCoalescer_do(t *testing.T) {
+	t.Run("Concurrent callers share one call", func(t *testing.T) {
+		// Arrange
+		coalescer := NewCoalescer(CoalescingConfig{})
+		release := make(chan struct{})
+		var calls atomic.Int32
+		send := func(context.Context) (*Response, error) {
+			calls.Add(1)
+			<-release
+			return newTextResponse("shared"), nil
+		}
+
+		// Act
+		const callers = 5
+		bodies := make([]string, callers)
+		var wg sync.WaitGroup
+		for i := range callers {
+			wg.Go(func() {
+				response, err := coalescer.do(context.Background(), "key", send)
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				bodies[i], _ = response.Body().AsString()
+			})
+		}
+		waitForCallers(t, coalescer, "key", callers)
+		close(release)
+		wg.Wait()
+
+		// Assert
+		if got := calls.Load(); got != 1 {
+			t.Errorf("calls got %d, want 1", got)
+		}
+		for i, body := range bodies {
+			if body != "shared" {
+				t.Errorf("caller %d body got %q, want %q", i, body, "shared")
+			}
+		}
+	})
+
+	t.Run("Error is shared", func(t *testing.T) {
+		// Arrange
+		coalescer := NewCoalescer(CoalescingConfig{})
+		release := make(chan struct{})
+		errSend := errors.New("connection refused")
+		send := func(context.Context) (*Response, error) {
+			<-release
+			return nil, errSend
+		}
+		errs := make(chan error, 2)
+		for range 2 {
+			go func() {
+				_, err := coalescer.do(context.Background(), "key", send)
+				errs <- err
+			}()
+		}
+		waitForCallers(t, coalescer, "key", 2)
+
+		// Act
+		close(release)
+
+		// Assert
+		for range 2 {
+			if err := <-errs; !errors.Is(err, errSend) {
+				t.Errorf("error got %v, want %v", err, errSend)
+			}
+		}
+	})
+
+	t.Run("Leader canceled", func(t *testing.T) {
+		// Arrange
+		coalescer := NewCoalescer(CoalescingConfig{})
+		release := make(chan struct{})
+		send := func(ctx context.Context) (*Response, error) {
+			select {
+			case <-release:
+				return newTextResponse("shared"), nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		leaderCtx, cancelLeader := context.WithCancel(context.Background())
+		leaderErrs := make(chan error, 1)
+		go func() {
+			_, err := coalescer.do(leaderCtx, "key", send)
+			leaderErrs <- err
+		}()
+		waitForCallers(t, coalescer, "key", 1)
+		type outcome struct {
+			body string
+			err  error
+		}
+		outcomes := make(chan outcome, 1)
+		go func() {
+			response, err := coalescer.do(context.Background(), "key", send)
+			if err != nil {
+				outcomes <- outcome{err: err}
+				return
+			}
+			body, _ := response.Body().AsString()
+			outcomes <- outcome{body: body}
+		}()
+		waitForCallers(t, coalescer, "key", 2)
+
+		// Act
+		cancelLeader()
+		leaderErr := <-leaderErrs
+		close(release)
+		waiter := <-outcomes
+
+		// Assert
+		if !errors.Is(leaderErr, context.Canceled) {
+			t.Errorf("leader error got %v, want %v", leaderErr, context.Canceled)
+		}
+		if waiter.err != nil {
+			t.Fatalf("waiter error got %v, want nil", waiter.err)
+		}
+		if waiter.body != "shared" {
+			t.Errorf("waiter body got %q, want %q", waiter.body, "shared")
+		}
+	})
+
+	t.Run("Call canceled once every caller left", func(t *testing.T) {
+		// Arrange
+		coalescer := NewCoalescer(CoalescingConfig{})
+		canceled := make(chan struct{})
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			_, _ = coalescer.do(ctx, "key", func(callCtx context.Context) (*Response, error) {
+				<-callCtx.Done()
+				close(canceled)
+				return nil, callCtx.Err()
+			})
+		}()
+		waitForCallers(t, coalescer, "key", 1)
+
+		// Act
+		cancel()
+
+		// Assert
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Fatal("shared call was not canceled")
+		}
+	})
+}
+
+// newCoalescingRequest returns a request to a fixed URL with the given method and headers.
+func newCoalescingRequest(m method.Type, header http.Header) *http.Request {
+	req, _ := http.NewRequest(m.String(), "https://example.com/config?env=prod", nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	return req
+}
+
+// newTextResponse returns a successful response with the given body.
+func newTextResponse(body string) *Response {
+	return newResponse(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/plain"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	})
+}
+
+// waitForCallers waits until the call with the given key has at least the given number of callers.
+func waitForCallers(t *testing.T, coalescer *Coalescer, key string, callers int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		coalescer.mu.Lock()
+		call, ok := coalescer.calls[key]
+		ready := ok && call.callers >= callers
+		coalescer.mu.Unlock()
+		if ready {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("call %q did not get %d callers", key, callers)
+}
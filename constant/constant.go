@@ -1,15 +1,27 @@
 package constant
 
 const (
-	ErrMsgClientValidation  = "invalid client attributes"
-	ErrMsgCreateRequest     = "failed to create request"
-	ErrMsgEmptyBaseURL      = "empty base URL"
-	ErrMsgMarshalJSON       = "failed to marshal JSON"
-	ErrMsgMarshalXML        = "failed to marshal XML"
-	ErrMsgParseProxyURL     = "failed to parse proxy URL"
-	ErrMsgParseQueryString  = "failed to parse query string"
-	ErrMsgParseURL          = "failed to parse URL"
-	ErrMsgRequestValidation = "invalid request attributes"
-	ErrMsgBeforeRequestHook = "before request hook failed"
-	ErrMsgSetBody           = "failed to set body"
+	ErrMsgBulkheadFull         = "bulkhead is full"
+	ErrMsgCircuitOpen          = "circuit breaker is open"
+	ErrMsgClientValidation     = "invalid client attributes"
+	ErrMsgCreateRequest        = "failed to create request"
+	ErrMsgEmptyBaseURL         = "empty base URL"
+	ErrMsgHedgeNonIdempotent   = "hedging requires an idempotent method"
+	ErrMsgLoadBalancerRequired = "load balancer options require a load-balanced client"
+	ErrMsgInvalidRateLimit     = "rate limit must be positive"
+	ErrMsgMarshalJSON          = "failed to marshal JSON"
+	ErrMsgMarshalXML           = "failed to marshal XML"
+	ErrMsgNoDNSRecords         = "no DNS records found"
+	ErrMsgParseEndpoints       = "failed to parse endpoints file"
+	ErrMsgParseProxyURL        = "failed to parse proxy URL"
+	ErrMsgParseQueryString     = "failed to parse query string"
+	ErrMsgParseURL             = "failed to parse URL"
+	ErrMsgRateLimitWait        = "rate limit wait canceled"
+	ErrMsgRelativeBaseURL      = "base URL must be absolute with a scheme and host"
+	ErrMsgRequestValidation    = "invalid request attributes"
+	ErrMsgRetryBudgetExhausted = "retry budget exhausted"
+	ErrMsgRetryCanceled        = "retry canceled"
+	ErrMsgRetryDeadline        = "retry deadline exceeded"
+	ErrMsgBeforeRequestHook    = "before request hook failed"
+	ErrMsgSetBody              = "failed to set body"
 )
@@ -51,6 +51,9 @@ const (
 	ProxyAuthenticate             Type = "Proxy-Authenticate"
 	ProxyAuthorization            Type = "Proxy-Authorization"
 	Range                         Type = "Range"
+	RateLimitPolicy               Type = "RateLimit-Policy"
+	RateLimitRemaining            Type = "RateLimit-Remaining"
+	RateLimitReset                Type = "RateLimit-Reset"
 	Referer                       Type = "Referer"
 	Refresh                       Type = "Refresh"
 	RetryAfter                    Type = "Retry-After"
@@ -65,6 +68,8 @@ const (
 	Via                           Type = "Via"
 	WWWAuthenticate               Type = "WWW-Authenticate"
 	Warning                       Type = "Warning"
+	XRateLimitRemaining           Type = "X-RateLimit-Remaining"
+	XRateLimitReset               Type = "X-RateLimit-Reset"
 	XRequestedWith                Type = "X-Requested-With"
 )
 
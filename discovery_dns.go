@@ -0,0 +1,218 @@
+package fastshot
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opus-domini/fast-shot/constant"
+)
+
+// Compile-time check that DNSEndpoints implements Endpoints.
+var _ Endpoints = (*DNSEndpoints)(nil)
+
+// Default values of DNSEndpointsConfig.
+const (
+	defaultDNSScheme          = "https"
+	defaultDNSRefreshInterval = 30 * time.Second
+	defaultDNSTimeout         = 5 * time.Second
+)
+
+type (
+	// Resolver is the interface that wraps the DNS lookups used by DNSEndpoints.
+	// *net.Resolver implements it.
+	Resolver interface {
+		LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+		LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+	}
+
+	// DNSEndpointsConfig configures the DNS discovery of the backends of a load-balanced client.
+	// Zero values fall back to the defaults documented on each field.
+	DNSEndpointsConfig struct {
+		// Name is the DNS name to resolve: the full SRV record name, such as
+		// "_api._tcp.example.com", when SRV is set, or else a hostname whose A and AAAA
+		// records are used.
+		Name string
+		// SRV resolves Name as an SRV record. Each target becomes a backend, with the record
+		// port, weight and priority.
+		SRV bool
+		// Port is the port of the backends resolved from A and AAAA records. Defaults to the
+		// port of the scheme.
+		Port int
+		// Scheme is the scheme of the base URLs. Defaults to "https". With A and AAAA records,
+		// the base URLs hold IP addresses, so HTTPS backends need certificates valid for them.
+		Scheme string
+		// RefreshInterval is the time between lookups. Defaults to 30 seconds.
+		RefreshInterval time.Duration
+		// Timeout bounds each lookup. Defaults to 5 seconds.
+		Timeout time.Duration
+		// Resolver performs the lookups. Defaults to net.DefaultResolver.
+		Resolver Resolver
+	}
+
+	// DNSEndpoints is an Endpoints source that discovers backends from DNS and refreshes them in
+	// the background. Lookups that fail or return no record keep the last known backends.
+	// Close stops the refresh.
+	DNSEndpoints struct {
+		config  DNSEndpointsConfig
+		set     *EndpointSet
+		ctx     context.Context
+		cancel  context.CancelFunc
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		lastErr error
+	}
+)
+
+// NewDNSEndpoints creates a DNSEndpoints source. It resolves the name once before returning, so
+// a client built right after starts with the discovered backends, then refreshes every interval.
+func NewDNSEndpoints(config DNSEndpointsConfig) *DNSEndpoints {
+	if config.Scheme == "" {
+		config.Scheme = defaultDNSScheme
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = defaultDNSRefreshInterval
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultDNSTimeout
+	}
+	if config.Resolver == nil {
+		config.Resolver = net.DefaultResolver
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &DNSEndpoints{
+		config: config,
+		set:    NewEndpointSet(nil),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	_ = d.Refresh(ctx)
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(config.RefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = d.Refresh(ctx)
+			}
+		}
+	}()
+
+	return d
+}
+
+// Config for DNSEndpoints returns the effective configuration.
+func (d *DNSEndpoints) Config() DNSEndpointsConfig {
+	return d.config
+}
+
+// Watch for DNSEndpoints applies the discovered endpoints to the membership, and every update after that.
+func (d *DNSEndpoints) Watch(membership Membership) {
+	d.set.Watch(membership)
+}
+
+// Endpoints for DNSEndpoints returns the last discovered endpoints.
+func (d *DNSEndpoints) Endpoints() []Endpoint {
+	return d.set.Endpoints()
+}
+
+// Err for DNSEndpoints returns the error of the last lookup, or nil if it succeeded.
+func (d *DNSEndpoints) Err() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastErr
+}
+
+// Refresh for DNSEndpoints resolves the name now and applies the result. On error, the last known
+// endpoints are kept.
+func (d *DNSEndpoints) Refresh(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	endpoints, err := d.lookup(ctx)
+	if err == nil {
+		err = d.set.Replace(endpoints)
+	}
+
+	d.mu.Lock()
+	d.lastErr = err
+	d.mu.Unlock()
+	return err
+}
+
+// Close for DNSEndpoints stops the background refresh. It is safe to call more than once.
+func (d *DNSEndpoints) Close() error {
+	d.cancel()
+	d.wg.Wait()
+	return nil
+}
+
+// lookup resolves the configured name into endpoints.
+func (d *DNSEndpoints) lookup(ctx context.Context) ([]Endpoint, error) {
+	if d.config.SRV {
+		return d.lookupSRV(ctx)
+	}
+	return d.lookupHost(ctx)
+}
+
+// lookupSRV resolves the SRV record into one endpoint per target.
+func (d *DNSEndpoints) lookupSRV(ctx context.Context) ([]Endpoint, error) {
+	_, records, err := d.config.Resolver.LookupSRV(ctx, "", "", d.config.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New(constant.ErrMsgNoDNSRecords)
+	}
+
+	endpoints := make([]Endpoint, 0, len(records))
+	for _, record := range records {
+		endpoints = append(endpoints, Endpoint{
+			BaseURL:  d.baseURL(strings.TrimSuffix(record.Target, "."), int(record.Port)),
+			Weight:   uint(record.Weight),
+			Priority: uint(record.Priority),
+		})
+	}
+	return endpoints, nil
+}
+
+// lookupHost resolves the A and AAAA records of the hostname into one endpoint per address.
+func (d *DNSEndpoints) lookupHost(ctx context.Context) ([]Endpoint, error) {
+	addresses, err := d.config.Resolver.LookupIPAddr(ctx, d.config.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(addresses) == 0 {
+		return nil, errors.New(constant.ErrMsgNoDNSRecords)
+	}
+
+	port := d.config.Port
+	if port == 0 {
+		port = 443
+		if d.config.Scheme == "http" {
+			port = 80
+		}
+	}
+
+	endpoints := make([]Endpoint, 0, len(addresses))
+	for _, address := range addresses {
+		endpoints = append(endpoints, Endpoint{BaseURL: d.baseURL(address.String(), port)})
+	}
+	return endpoints, nil
+}
+
+// baseURL builds the base URL of a host and port.
+func (d *DNSEndpoints) baseURL(host string, port int) string {
+	return (&url.URL{Scheme: d.config.Scheme, Host: net.JoinHostPort(host, strconv.Itoa(port))}).String()
+}
@@ -0,0 +1,178 @@
+package fastshot
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeResolver is a Resolver that serves records from memory.
+type fakeResolver struct {
+	mu      sync.Mutex
+	srv     []*net.SRV
+	ips     []net.IPAddr
+	err     error
+	lookups int
+}
+
+func (r *fakeResolver) LookupSRV(_ context.Context, _, _, _ string) (string, []*net.SRV, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	return "", r.srv, r.err
+}
+
+func (r *fakeResolver) LookupIPAddr(_ context.Context, _ string) ([]net.IPAddr, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	return r.ips, r.err
+}
+
+func (r *fakeResolver) set(srv []*net.SRV, ips []net.IPAddr, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.srv, r.ips, r.err = srv, ips, err
+}
+
+func TestDNSEndpoints_Lookup(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      DNSEndpointsConfig
+		srv         []*net.SRV
+		ips         []net.IPAddr
+		err         error
+		expectError bool
+		expected    []Endpoint
+	}{
+		{
+			name:   "SRV targets",
+			config: DNSEndpointsConfig{Name: "_api._tcp.example.com", SRV: true},
+			srv: []*net.SRV{
+				{Target: "backup.example.com.", Port: 8443, Priority: 20, Weight: 1},
+				{Target: "a.example.com.", Port: 8443, Priority: 10, Weight: 3},
+				{Target: "b.example.com.", Port: 9443, Priority: 10, Weight: 1},
+			},
+			expected: []Endpoint{
+				{BaseURL: "https://backup.example.com:8443", Weight: 1, Priority: 20},
+				{BaseURL: "https://a.example.com:8443", Weight: 3, Priority: 10},
+				{BaseURL: "https://b.example.com:9443", Weight: 1, Priority: 10},
+			},
+		},
+		{
+			name:   "A and AAAA records with the scheme port",
+			config: DNSEndpointsConfig{Name: "api.example.com", Scheme: "http"},
+			ips:    []net.IPAddr{{IP: net.ParseIP("10.0.0.1")}, {IP: net.ParseIP("fd00::1")}},
+			expected: []Endpoint{
+				{BaseURL: "http://10.0.0.1:80"},
+				{BaseURL: "http://[fd00::1]:80"},
+			},
+		},
+		{
+			name:     "A records with an explicit port",
+			config:   DNSEndpointsConfig{Name: "api.example.com", Port: 8080},
+			ips:      []net.IPAddr{{IP: net.ParseIP("10.0.0.1")}},
+			expected: []Endpoint{{BaseURL: "https://10.0.0.1:8080"}},
+		},
+		{
+			name:        "No records",
+			config:      DNSEndpointsConfig{Name: "_api._tcp.example.com", SRV: true},
+			expectError: true,
+		},
+		{
+			name:        "Lookup error",
+			config:      DNSEndpointsConfig{Name: "api.example.com"},
+			err:         errors.New("no such host"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			resolver := &fakeResolver{srv: tt.srv, ips: tt.ips, err: tt.err}
+			tt.config.Resolver = resolver
+			tt.config.RefreshInterval = time.Hour
+
+			// Act
+			endpoints := NewDNSEndpoints(tt.config)
+			defer func() { _ = endpoints.Close() }()
+
+			// Assert
+			if err := endpoints.Err(); (err != nil) != tt.expectError {
+				t.Fatalf("error got %v, want error %v", err, tt.expectError)
+			}
+			if got := endpoints.Endpoints(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDNSEndpoints_Refresh(t *testing.T) {
+	// Arrange
+	resolver := &fakeResolver{ips: []net.IPAddr{{IP: net.ParseIP("10.0.0.1")}}}
+	endpoints := NewDNSEndpoints(DNSEndpointsConfig{Name: "api.example.com", Resolver: resolver, RefreshInterval: time.Hour})
+	defer func() { _ = endpoints.Close() }()
+	base := newBalancedBaseURL(nil)
+	endpoints.Watch(base)
+
+	// Act
+	resolver.set(nil, []net.IPAddr{{IP: net.ParseIP("10.0.0.2")}, {IP: net.ParseIP("10.0.0.3")}}, nil)
+	updateErr := endpoints.Refresh(context.Background())
+	resolver.set(nil, nil, errors.New("server misbehaving"))
+	failErr := endpoints.Refresh(context.Background())
+
+	// Assert
+	if updateErr != nil {
+		t.Fatalf("update error got %v, want nil", updateErr)
+	}
+	if failErr == nil {
+		t.Fatal("failed refresh error got nil, want error")
+	}
+	var got []string
+	for _, backend := range base.Backends() {
+		got = append(got, backend.URL.String())
+	}
+	want := []string{"https://10.0.0.2:443", "https://10.0.0.3:443"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestDNSEndpoints_RefreshInterval(t *testing.T) {
+	// Arrange
+	resolver := &fakeResolver{ips: []net.IPAddr{{IP: net.ParseIP("10.0.0.1")}}}
+	endpoints := NewDNSEndpoints(DNSEndpointsConfig{Name: "api.example.com", Resolver: resolver, RefreshInterval: 10 * time.Millisecond})
+	base := newBalancedBaseURL(nil)
+	endpoints.Watch(base)
+
+	// Act
+	resolver.set(nil, []net.IPAddr{{IP: net.ParseIP("10.0.0.2")}}, nil)
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if backends := base.Backends(); len(backends) == 1 && backends[0].URL.Host == "10.0.0.2:443" {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	_ = endpoints.Close()
+	resolver.mu.Lock()
+	lookups := resolver.lookups
+	resolver.mu.Unlock()
+	time.Sleep(30 * time.Millisecond)
+
+	// Assert
+	if got := base.Backends(); len(got) != 1 || got[0].URL.Host != "10.0.0.2:443" {
+		t.Errorf("got %v, want [https://10.0.0.2:443]", got)
+	}
+	resolver.mu.Lock()
+	defer resolver.mu.Unlock()
+	if resolver.lookups != lookups {
+		t.Errorf("lookups after close got %d, want %d", resolver.lookups, lookups)
+	}
+}
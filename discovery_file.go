@@ -0,0 +1,236 @@
+package fastshot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opus-domini/fast-shot/constant"
+)
+
+// Compile-time check that FileEndpoints implements Endpoints.
+var _ Endpoints = (*FileEndpoints)(nil)
+
+// Default values of FileEndpointsConfig.
+const (
+	defaultFilePollInterval = 5 * time.Second
+)
+
+type (
+	// FileEndpointsConfig configures the file that lists the backends of a load-balanced client.
+	// Zero values fall back to the defaults documented on each field.
+	//
+	// The file is either a JSON array of objects:
+	//
+	//	[{"base_url": "https://api1.example.com", "weight": 2, "zone": "eu-west-1a", "priority": 0}]
+	//
+	// or plain text, with one base URL per line followed by optional weight, zone and priority
+	// attributes. Blank lines and lines starting with # are ignored:
+	//
+	//	https://api1.example.com weight=2 zone=eu-west-1a
+	//	https://api2.example.com priority=1
+	FileEndpointsConfig struct {
+		// Path is the path of the file.
+		Path string
+		// PollInterval is the time between checks of the file modification time. Defaults to 5 seconds.
+		PollInterval time.Duration
+	}
+
+	// FileEndpoints is an Endpoints source that reads backends from a file and reloads it when
+	// it changes. Files that cannot be read or parsed, or that list no valid backend, are
+	// rejected and the last good backends are kept. Close stops watching the file.
+	FileEndpoints struct {
+		config  FileEndpointsConfig
+		set     *EndpointSet
+		ctx     context.Context
+		cancel  context.CancelFunc
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		modTime time.Time
+		size    int64
+		lastErr error
+	}
+
+	// fileEndpoint is the JSON representation of an Endpoint.
+	fileEndpoint struct {
+		BaseURL  string `json:"base_url"`
+		Weight   uint   `json:"weight"`
+		Zone     string `json:"zone"`
+		Priority uint   `json:"priority"`
+	}
+)
+
+// NewFileEndpoints creates a FileEndpoints source. It loads the file once before returning, so a
+// client built right after starts with the listed backends, then polls it for changes.
+func NewFileEndpoints(config FileEndpointsConfig) *FileEndpoints {
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultFilePollInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &FileEndpoints{
+		config: config,
+		set:    NewEndpointSet(nil),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	_ = f.Reload()
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		ticker := time.NewTicker(config.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if f.changed() {
+					_ = f.Reload()
+				}
+			}
+		}
+	}()
+
+	return f
+}
+
+// Config for FileEndpoints returns the effective configuration.
+func (f *FileEndpoints) Config() FileEndpointsConfig {
+	return f.config
+}
+
+// Watch for FileEndpoints applies the listed endpoints to the membership, and every update after that.
+func (f *FileEndpoints) Watch(membership Membership) {
+	f.set.Watch(membership)
+}
+
+// Endpoints for FileEndpoints returns the last good endpoints.
+func (f *FileEndpoints) Endpoints() []Endpoint {
+	return f.set.Endpoints()
+}
+
+// Err for FileEndpoints returns the error of the last load, or nil if it succeeded.
+func (f *FileEndpoints) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lastErr
+}
+
+// Reload for FileEndpoints reads the file now and applies it. On error, the last good endpoints are kept.
+func (f *FileEndpoints) Reload() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if info, err := os.Stat(f.config.Path); err == nil {
+		f.modTime, f.size = info.ModTime(), info.Size()
+	}
+
+	endpoints, err := f.load()
+	if err == nil {
+		err = f.set.Replace(endpoints)
+	}
+	f.lastErr = err
+	return err
+}
+
+// Close for FileEndpoints stops watching the file. It is safe to call more than once.
+func (f *FileEndpoints) Close() error {
+	f.cancel()
+	f.wg.Wait()
+	return nil
+}
+
+// changed reports whether the file modification time or size differs from the last load.
+func (f *FileEndpoints) changed() bool {
+	info, err := os.Stat(f.config.Path)
+	if err != nil {
+		return false
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return !info.ModTime().Equal(f.modTime) || info.Size() != f.size
+}
+
+// load reads and parses the file.
+func (f *FileEndpoints) load() ([]Endpoint, error) {
+	content, err := os.ReadFile(f.config.Path)
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := parseEndpointsFile(content)
+	if err != nil {
+		return nil, errors.Join(errors.New(constant.ErrMsgParseEndpoints), err)
+	}
+	return endpoints, nil
+}
+
+// parseEndpointsFile parses a JSON or plain-text endpoints file. Every base URL must be absolute
+// with a scheme and host.
+func parseEndpointsFile(content []byte) ([]Endpoint, error) {
+	content = bytes.TrimSpace(content)
+	if bytes.HasPrefix(content, []byte("[")) {
+		var entries []fileEndpoint
+		if err := json.Unmarshal(content, &entries); err != nil {
+			return nil, err
+		}
+		endpoints := make([]Endpoint, 0, len(entries))
+		for index, entry := range entries {
+			if _, err := parseBaseURL(entry.BaseURL); err != nil {
+				return nil, fmt.Errorf("entry %d: %w", index, err)
+			}
+			endpoints = append(endpoints, Endpoint{
+				BaseURL:  entry.BaseURL,
+				Weight:   entry.Weight,
+				Zone:     entry.Zone,
+				Priority: entry.Priority,
+			})
+		}
+		return endpoints, nil
+	}
+
+	var endpoints []Endpoint
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if _, err := parseBaseURL(fields[0]); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		endpoint := Endpoint{BaseURL: fields[0]}
+		for _, field := range fields[1:] {
+			key, value, _ := strings.Cut(field, "=")
+			switch key {
+			case "weight", "priority":
+				number, err := strconv.ParseUint(value, 10, 0)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid %s %q", line, key, value)
+				}
+				if key == "weight" {
+					endpoint.Weight = uint(number)
+				} else {
+					endpoint.Priority = uint(number)
+				}
+			case "zone":
+				endpoint.Zone = value
+			default:
+				return nil, fmt.Errorf("line %d: unknown attribute %q", line, field)
+			}
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, scanner.Err()
+}
@@ -0,0 +1,202 @@
+package fastshot
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseEndpointsFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectError bool
+		expected    []Endpoint
+	}{
+		{
+			name:    "JSON",
+			content: `[{"base_url": "https://a.com", "weight": 2, "zone": "eu-1"}, {"base_url": "https://b.com", "priority": 1}]`,
+			expected: []Endpoint{
+				{BaseURL: "https://a.com", Weight: 2, Zone: "eu-1"},
+				{BaseURL: "https://b.com", Priority: 1},
+			},
+		},
+		{
+			name:    "Plain text",
+			content: "# backends\nhttps://a.com weight=2 zone=eu-1\n\n  https://b.com zone=eu-2 priority=1\nhttps://c.com\n",
+			expected: []Endpoint{
+				{BaseURL: "https://a.com", Weight: 2, Zone: "eu-1"},
+				{BaseURL: "https://b.com", Zone: "eu-2", Priority: 1},
+				{BaseURL: "https://c.com"},
+			},
+		},
+		{
+			name:        "Invalid JSON",
+			content:     `[{"base_url": "https://a.com"`,
+			expectError: true,
+		},
+		{
+			name:        "Invalid weight",
+			content:     "https://a.com weight=heavy",
+			expectError: true,
+		},
+		{
+			name:        "Invalid priority",
+			content:     "https://a.com priority=-1",
+			expectError: true,
+		},
+		{
+			name:        "JSON relative base URL",
+			content:     `[{"base_url": "https://a.com"}, {"base_url": "/api"}]`,
+			expectError: true,
+		},
+		{
+			name:        "Base URL without scheme",
+			content:     "https://a.com\napi.example.com\n",
+			expectError: true,
+		},
+		{
+			name:        "Base URL without host",
+			content:     "https:///api",
+			expectError: true,
+		},
+		{
+			name:        "Unknown attribute",
+			content:     "https://a.com region=eu",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got, err := parseEndpointsFile([]byte(tt.content))
+
+			// Assert
+			if (err != nil) != tt.expectError {
+				t.Fatalf("error got %v, want error %v", err, tt.expectError)
+			}
+			if !tt.expectError && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFileEndpoints_Reload(t *testing.T) {
+	tests := []struct {
+		name        string
+		update      string
+		expectError bool
+		expected    []string
+	}{
+		{
+			name:     "Valid file is applied",
+			update:   "https://b.com\nhttps://c.com weight=2\n",
+			expected: []string{"https://b.com", "https://c.com"},
+		},
+		{
+			name:        "Empty file keeps the last good set",
+			update:      "",
+			expectError: true,
+			expected:    []string{"https://a.com"},
+		},
+		{
+			name:        "Invalid base URL keeps the last good set",
+			update:      "https://b.com\n:%^:\n",
+			expectError: true,
+			expected:    []string{"https://a.com"},
+		},
+		{
+			name:        "Relative base URL keeps the last good set",
+			update:      "https://b.com\nlocalhost:8080/api\n",
+			expectError: true,
+			expected:    []string{"https://a.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(t.TempDir(), "endpoints.txt")
+			if err := os.WriteFile(path, []byte("https://a.com\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			endpoints := NewFileEndpoints(FileEndpointsConfig{Path: path, PollInterval: time.Hour})
+			defer func() { _ = endpoints.Close() }()
+			base := newBalancedBaseURL(nil)
+			endpoints.Watch(base)
+
+			// Act
+			if err := os.WriteFile(path, []byte(tt.update), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			err := endpoints.Reload()
+
+			// Assert
+			if (err != nil) != tt.expectError {
+				t.Fatalf("error got %v, want error %v", err, tt.expectError)
+			}
+			var got []string
+			for _, backend := range base.Backends() {
+				got = append(got, backend.URL.String())
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFileEndpoints_MissingFile(t *testing.T) {
+	// Act
+	endpoints := NewFileEndpoints(FileEndpointsConfig{Path: filepath.Join(t.TempDir(), "missing.json"), PollInterval: time.Hour})
+	defer func() { _ = endpoints.Close() }()
+
+	// Assert
+	if endpoints.Err() == nil {
+		t.Error("error got nil, want error")
+	}
+	if got := endpoints.Endpoints(); len(got) != 0 {
+		t.Errorf("got %v, want no endpoints", got)
+	}
+}
+
+func TestFileEndpoints_PollsForChanges(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	if err := os.WriteFile(path, []byte(`[{"base_url": "https://a.com"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	endpoints := NewFileEndpoints(FileEndpointsConfig{Path: path, PollInterval: 10 * time.Millisecond})
+	defer func() { _ = endpoints.Close() }()
+	base := newBalancedBaseURL(nil)
+	endpoints.Watch(base)
+
+	// Act
+	if err := os.WriteFile(path, []byte(`[{"base_url": "https://b.com", "zone": "eu-1"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if backends := base.Backends(); len(backends) == 1 && backends[0].URL.Host == "b.com" {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Assert
+	want := []Endpoint{{BaseURL: "https://b.com", Zone: "eu-1"}}
+	if got := endpoints.Endpoints(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := base.Backends(); len(got) != 1 || got[0].URL.Host != "b.com" {
+		t.Errorf("balancer backends got %v, want [https://b.com]", got)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"log/slog"
+	"time"
 
 	fastshot "github.com/opus-domini/fast-shot"
 	"github.com/opus-domini/fast-shot/examples/server"
@@ -23,9 +24,19 @@ func main() {
 	ts3 := serverManager.NewServer()
 	defer ts3.Close()
 
+	// Probe the "/" health endpoint of every server in the background.
+	// Servers that fail the probes are taken out of rotation until they recover.
+	checker := fastshot.NewHealthChecker(fastshot.HealthCheckConfig{
+		Path:     "/",
+		Interval: time.Second,
+	})
+	defer func() { _ = checker.Close() }()
+
 	// Create a custom client with client-side load balancing.
-	// The client round-robins the requests between the servers.
-	client := fastshot.NewClientLoadBalancer([]string{ts1.URL, ts2.URL, ts3.URL}).Build()
+	// The client round-robins the requests between the healthy servers.
+	client := fastshot.NewClientLoadBalancer([]string{ts1.URL, ts2.URL, ts3.URL}).
+		LoadBalancer().WithHealthCheck(checker).
+		Build()
 
 	// Perform health checks on the servers
 	for range 9 {
@@ -0,0 +1,95 @@
+package fastshot
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// errHedgeLost is the cause of the cancellation of hedged attempts that lost the race.
+var errHedgeLost = errors.New("hedged attempt lost")
+
+const (
+	// defaultLatencyTrackerSize is the number of recent latencies a LatencyTracker keeps by default.
+	defaultLatencyTrackerSize = 100
+	// minLatencySamples is the number of samples a LatencyTracker needs before its percentiles are used.
+	minLatencySamples = 10
+)
+
+type (
+	// HedgeConfig configures request hedging. When no response arrives within the hedge delay, a
+	// duplicate attempt is sent, preferably to another base URL, and the first response wins.
+	HedgeConfig struct {
+		// Delay is the time to wait for a response before sending each hedged attempt.
+		// It is ignored when Tracker is set.
+		Delay time.Duration
+		// Tracker, when set, derives the delay from the recent latency of the hedged requests.
+		Tracker *LatencyTracker
+		// Percentile is the percentile of the recent latency, between 0 and 100, used as the delay
+		// when Tracker is set.
+		Percentile float64
+		// MaxHedges is the maximum number of attempts sent in addition to the original one.
+		MaxHedges uint
+	}
+
+	// LatencyTracker keeps the most recent latencies of a set of requests to derive hedge delays
+	// from their percentiles. It is safe for concurrent use, so a single tracker can be shared by
+	// every request to the same endpoint.
+	LatencyTracker struct {
+		mutex    sync.Mutex
+		samples  []time.Duration
+		next     int
+		full     bool
+		fallback time.Duration
+	}
+)
+
+// delay returns the time to wait before sending the next hedged attempt.
+func (c *HedgeConfig) delay() time.Duration {
+	if c.Tracker != nil {
+		return c.Tracker.Percentile(c.Percentile)
+	}
+	return c.Delay
+}
+
+// NewLatencyTracker creates a LatencyTracker that keeps the given number of recent latencies
+// (100 when not positive). Percentiles return the fallback until enough latencies are observed.
+func NewLatencyTracker(size int, fallback time.Duration) *LatencyTracker {
+	if size <= 0 {
+		size = defaultLatencyTrackerSize
+	}
+	return &LatencyTracker{
+		samples:  make([]time.Duration, 0, size),
+		fallback: fallback,
+	}
+}
+
+// Observe records a latency, replacing the oldest one once the tracker is full.
+func (t *LatencyTracker) Observe(latency time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if !t.full {
+		t.samples = append(t.samples, latency)
+		t.full = len(t.samples) == cap(t.samples)
+		return
+	}
+	t.samples[t.next] = latency
+	t.next = (t.next + 1) % len(t.samples)
+}
+
+// Percentile returns the given percentile, between 0 and 100, of the recent latencies using the
+// nearest-rank method, or the fallback while fewer than 10 latencies have been observed.
+func (t *LatencyTracker) Percentile(percentile float64) time.Duration {
+	t.mutex.Lock()
+	samples, size := slices.Clone(t.samples), cap(t.samples)
+	t.mutex.Unlock()
+	if len(samples) < min(minLatencySamples, size) {
+		return t.fallback
+	}
+
+	slices.Sort(samples)
+	rank := int(math.Ceil(math.Min(math.Max(percentile, 0), 100) / 100 * float64(len(samples))))
+	return samples[max(rank, 1)-1]
+}
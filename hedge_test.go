@@ -0,0 +1,101 @@
+package fastshot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyTracker_Percentile(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       int
+		latencies  []time.Duration
+		percentile float64
+		expected   time.Duration
+	}{
+		{
+			name:       "Fallback without enough samples",
+			size:       100,
+			latencies:  []time.Duration{time.Millisecond, 2 * time.Millisecond},
+			percentile: 50,
+			expected:   time.Second,
+		},
+		{
+			name:       "Median",
+			size:       10,
+			latencies:  durations(1, 10),
+			percentile: 50,
+			expected:   5 * time.Millisecond,
+		},
+		{
+			name:       "High percentile",
+			size:       10,
+			latencies:  durations(1, 10),
+			percentile: 95,
+			expected:   10 * time.Millisecond,
+		},
+		{
+			name:       "Zero percentile",
+			size:       10,
+			latencies:  durations(1, 10),
+			percentile: 0,
+			expected:   time.Millisecond,
+		},
+		{
+			name:       "Oldest samples are replaced",
+			size:       10,
+			latencies:  durations(1, 15),
+			percentile: 0,
+			expected:   6 * time.Millisecond,
+		},
+		{
+			name:       "Small tracker",
+			size:       1,
+			latencies:  []time.Duration{3 * time.Millisecond},
+			percentile: 99,
+			expected:   3 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			tracker := NewLatencyTracker(tt.size, time.Second)
+			for _, latency := range tt.latencies {
+				tracker.Observe(latency)
+			}
+
+			// Act
+			got := tracker.Percentile(tt.percentile)
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHedgeConfig_Delay(t *testing.T) {
+	// Arrange
+	tracker := NewLatencyTracker(0, 40*time.Millisecond)
+	fixed := &HedgeConfig{Delay: 10 * time.Millisecond, MaxHedges: 1}
+	percentile := &HedgeConfig{Delay: 10 * time.Millisecond, Tracker: tracker, Percentile: 99, MaxHedges: 1}
+
+	// Act & Assert
+	if got := fixed.delay(); got != 10*time.Millisecond {
+		t.Errorf("fixed delay got %v, want %v", got, 10*time.Millisecond)
+	}
+	if got := percentile.delay(); got != 40*time.Millisecond {
+		t.Errorf("percentile delay got %v, want %v", got, 40*time.Millisecond)
+	}
+}
+
+// durations returns the latencies from first to last milliseconds.
+func durations(first, last int) []time.Duration {
+	var latencies []time.Duration
+	for ms := first; ms <= last; ms++ {
+		latencies = append(latencies, time.Duration(ms)*time.Millisecond)
+	}
+	return latencies
+}
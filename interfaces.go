@@ -61,6 +61,67 @@ type ClientConfig interface {
 	AddAfterResponseHook(func(*http.Request, *http.Response))
 }
 
+// ConfigRetry is the interface that wraps the basic methods for the client retry policy.
+//
+// ClientConfig implementations that support client-wide retries implement this interface. The
+// retry configuration is the default policy copied into every request, and the retry budget, if
+// any, caps the retries of every request of the client.
+type ConfigRetry interface {
+	RetryConfig() *RetryConfig
+	RetryBudget() *RetryBudget
+	SetRetryBudget(budget *RetryBudget)
+}
+
+// ConfigLifecycleHooks is the interface that wraps the basic methods for the client retry, error
+// and give-up hooks.
+//
+// ClientConfig implementations that support these hooks implement this interface; the request
+// engine runs them along with the hooks set on each request.
+type ConfigLifecycleHooks interface {
+	RetryHooks() []func(*http.Request, RetryEvent)
+	ErrorHooks() []func(*http.Request, error)
+	GiveUpHooks() []func(*http.Request, error)
+	AddRetryHook(func(*http.Request, RetryEvent))
+	AddErrorHook(func(*http.Request, error))
+	AddGiveUpHook(func(*http.Request, error))
+}
+
+// ConfigCircuitBreaker is the interface that wraps the basic methods for the client circuit breaker.
+//
+// ClientConfig implementations that support circuit breaking implement this interface; the
+// request engine asks the circuit breaker, if any, to admit every attempt.
+type ConfigCircuitBreaker interface {
+	CircuitBreaker() *CircuitBreaker
+	SetCircuitBreaker(breaker *CircuitBreaker)
+}
+
+// ConfigRateLimits is the interface that wraps the basic methods for the client rate limits.
+//
+// ClientConfig implementations that support rate limiting implement this interface; every
+// attempt waits for the rate limits, if any, before being sent.
+type ConfigRateLimits interface {
+	RateLimits() *RateLimits
+	SetRateLimits(limits *RateLimits)
+}
+
+// ConfigBulkhead is the interface that wraps the basic methods for the client bulkhead.
+//
+// ClientConfig implementations that support concurrency limiting implement this interface;
+// every attempt takes a slot of the bulkhead, if any, before being sent.
+type ConfigBulkhead interface {
+	Bulkhead() *Bulkhead
+	SetBulkhead(bulkhead *Bulkhead)
+}
+
+// ConfigCoalescer is the interface that wraps the basic methods for the client request coalescer.
+//
+// ClientConfig implementations that support request coalescing implement this interface;
+// identical requests share the call of the coalescer, if any.
+type ConfigCoalescer interface {
+	Coalescer() *Coalescer
+	SetCoalescer(coalescer *Coalescer)
+}
+
 // ConfigHttpClient is the interface that wraps the basic methods for configuring the underlying HTTP client.
 //
 // This interface is essential for providing fine-grained control over the HTTP client used for
@@ -144,6 +205,88 @@ type ConfigBaseURL interface {
 	BaseURL() *url.URL
 }
 
+// BaseURLSelector is the interface that wraps the basic method for selecting a base URL per attempt.
+//
+// ConfigBaseURL implementations that manage several base URLs implement this interface so the
+// retry engine can steer each attempt, e.g. away from backends that already failed for the
+// same request. Implementations without a choice to make can simply ignore the criteria.
+//
+// Example usage:
+//
+//	response, err := client.GET("/users").
+//		Retry().SetConstantBackoff(100*time.Millisecond, 3).
+//		Retry().WithFailover().
+//		Send()
+//
+// When every base URL matches an exclusion, the selector still returns one of them, so retries
+// keep going instead of failing without a backend.
+type BaseURLSelector interface {
+	SelectBaseURL(criteria BaseURLCriteria) *url.URL
+}
+
+// BaseURLObserver is the interface that wraps the basic methods for tracking requests per base URL.
+//
+// ConfigBaseURL implementations that balance load across several base URLs implement this
+// interface to learn how busy and how reliable each backend is. The request engine calls
+// RequestStarted right before an attempt is sent and calls the returned function once the
+// attempt fails or its response body is closed, so a request is in flight for as long as its
+// body is being read. Implementations that have nothing to track return nil. RecordOutcome is
+// called with the response or transport error of every attempt that reached the HTTP client.
+type BaseURLObserver interface {
+	RequestStarted(baseURL *url.URL) (finished func())
+	RecordOutcome(baseURL *url.URL, response *http.Response, err error)
+}
+
+// HashKeyProvider is the interface that wraps the basic method for deriving the hash key of a request.
+//
+// ConfigBaseURL implementations that pin requests to base URLs implement this interface so the
+// request engine can compute the key of each request before choosing its base URL. The function
+// receives a request with the final headers, cookies and query parameters, but without body or
+// base URL. A nil function means requests have no key unless they set one explicitly.
+type HashKeyProvider interface {
+	HashKeyFunc() HashKeyFunc
+}
+
+// BaseURLBalancer is the interface that wraps the basic method for telling load-balanced base URLs apart.
+//
+// ConfigBaseURL implementations that spread requests across equivalent base URLs implement this
+// interface, so features that identify requests, such as coalescing, leave the base URL out: it
+// is picked per attempt, and the same request may be sent to any of them.
+type BaseURLBalancer interface {
+	Balanced() bool
+}
+
+// Endpoints is the interface that wraps the basic method for sourcing the backends of a load-balanced client.
+//
+// An Endpoints source pushes the backends of a client as they change, e.g. from a service
+// registry or DNS. Watch is called once when the client is built: the source applies its current
+// endpoints to the membership before returning, if it has any, and every update after that.
+//
+// Example usage:
+//
+//	endpoints := fastshot.NewEndpointSet([]fastshot.Endpoint{
+//		{BaseURL: "https://api1.example.com"},
+//		{BaseURL: "https://api2.example.com"},
+//	})
+//	client := fastshot.NewClientLoadBalancerFromEndpoints(endpoints).Build()
+//
+//	// Later, while the client is in use
+//	_ = endpoints.Add(fastshot.Endpoint{BaseURL: "https://api3.example.com"})
+//	_ = endpoints.Drain(ctx, "https://api1.example.com")
+type Endpoints interface {
+	Watch(membership Membership)
+}
+
+// Membership is the interface that wraps the basic methods for updating the backends of a load-balanced client.
+//
+// SetEndpoints replaces the backends; invalid or empty updates are rejected and the current
+// backends are kept. WaitDrained waits until the requests in flight to a base URL finish, so a
+// backend taken out of the membership can be shut down safely.
+type Membership interface {
+	SetEndpoints(endpoints []Endpoint) error
+	WaitDrained(ctx context.Context, baseURL string) error
+}
+
 // ClientHttpMethods is the interface that wraps the basic HTTP methods for making requests.
 //
 // This interface is fundamental to the library as it provides a clean, method-based API for
@@ -240,6 +383,13 @@ type BuilderCookie[T any] interface {
 // *http.Request and optionally abort by returning an error. Post-response hooks
 // are observational and receive both the request and response.
 //
+// Retry, error and give-up hooks are observational too. Retry hooks fire when a retry is
+// scheduled, with the failed attempt number, the delay and the cause. Error hooks fire when
+// an attempt fails with a transport error, where no response is available. Give-up hooks
+// fire once when the request finally fails and Send returns an error, including when an attempt
+// is rejected before being sent, e.g. by an open circuit breaker; they then receive the request
+// that would have been sent.
+//
 // Example usage for client-level hooks:
 //
 //	client := fastshot.NewClient("https://api.example.com").
@@ -250,6 +400,9 @@ type BuilderCookie[T any] interface {
 //		Hook().OnAfterResponse(func(req *http.Request, resp *http.Response) {
 //			log.Printf("%s %s → %d", req.Method, req.URL, resp.StatusCode)
 //		}).
+//		Hook().OnRetry(func(req *http.Request, event fastshot.RetryEvent) {
+//			log.Printf("retrying %s in %s after attempt %d: %v", req.URL, event.Delay, event.Attempt, event.Cause)
+//		}).
 //		Build()
 //
 // The generic type parameter T allows this interface to be used with both ClientBuilder
@@ -257,6 +410,9 @@ type BuilderCookie[T any] interface {
 type BuilderHook[T any] interface {
 	OnBeforeRequest(hook func(*http.Request) error) *T
 	OnAfterResponse(hook func(*http.Request, *http.Response)) *T
+	OnRetry(hook func(*http.Request, RetryEvent)) *T
+	OnError(hook func(*http.Request, error)) *T
+	OnGiveUp(hook func(*http.Request, error)) *T
 }
 
 // BuilderAuth is the interface that wraps the basic methods for setting HTTP authentication.
@@ -285,6 +441,123 @@ type BuilderAuth[T any] interface {
 	BasicAuth(username, password string) *T
 }
 
+// BuilderLoadBalancer is the interface that wraps the basic methods for configuring client load balancing.
+//
+// These options only apply to clients created with NewClientLoadBalancer or
+// NewClientWeightedLoadBalancer; using them on a single base URL client records a validation error.
+//
+// Example usage:
+//
+//	checker := fastshot.NewHealthChecker(fastshot.HealthCheckConfig{
+//		Path:     "/health",
+//		Interval: 5 * time.Second,
+//	})
+//	defer checker.Close()
+//
+//	client := fastshot.NewClientLoadBalancer([]string{
+//		"https://api1.example.com",
+//		"https://api2.example.com",
+//	}).
+//		LoadBalancer().WithStrategy(fastshot.NewPowerOfTwoChoicesStrategy()).
+//		LoadBalancer().WithHealthCheck(checker).
+//		LoadBalancer().WithOutlierDetection(fastshot.OutlierDetectionConfig{ConsecutiveFailures: 5}).
+//		Build()
+type BuilderLoadBalancer[T any] interface {
+	WithStrategy(strategy LoadBalancingStrategy) *T
+	WithHealthCheck(checker *HealthChecker) *T
+	WithOutlierDetection(config OutlierDetectionConfig) *T
+	WithHashKey(hashKey HashKeyFunc) *T
+	WithPriorityGroups(config PriorityGroupsConfig) *T
+	WithSlowStart(config SlowStartConfig) *T
+}
+
+// BuilderCircuitBreaker is the interface that wraps the basic methods for configuring the client circuit breaker.
+//
+// The circuit breaker keeps one circuit per base URL. Once a circuit opens, requests to its base URL
+// fail fast with ErrCircuitOpen until the cooldown is over and probe requests succeed again. Load-balanced
+// clients send the requests to the base URLs whose circuit is not open instead.
+//
+// Example usage:
+//
+//	client := fastshot.NewClient("https://api.example.com").
+//		CircuitBreaker().Enable(fastshot.CircuitBreakerConfig{
+//			FailureRate:      0.5,
+//			MinRequests:      20,
+//			Cooldown:         15 * time.Second,
+//			HalfOpenRequests: 3,
+//		}).
+//		Build()
+type BuilderCircuitBreaker[T any] interface {
+	Enable(config CircuitBreakerConfig) *T
+	SetCustom(breaker *CircuitBreaker) *T
+}
+
+// BuilderRateLimit is the interface that wraps the basic methods for configuring client-side rate limiting.
+//
+// Each limiter is a token bucket with a rate, in requests per second, and a burst. Requests wait for a
+// token before being sent, and give up when their context is done first. Limits can apply to every
+// request, to each host, or to the requests under a path prefix; a request waits for all that apply.
+//
+// Example usage:
+//
+//	client := fastshot.NewClient("https://partner.example.com").
+//		RateLimit().Set(50, 10).
+//		RateLimit().SetPathPrefix("/search", 5, 1).
+//		RateLimit().WithHeaderAdaptation().
+//		Build()
+type BuilderRateLimit[T any] interface {
+	Set(rate float64, burst uint) *T
+	SetCustom(limiter *RateLimiter) *T
+	SetPerHost(rate float64, burst uint) *T
+	SetPathPrefix(prefix string, rate float64, burst uint) *T
+	WithHeaderAdaptation() *T
+}
+
+// BuilderBulkhead is the interface that wraps the basic methods for configuring the client bulkhead.
+//
+// The bulkhead caps the requests in flight. A request holds its slot until its response body is
+// closed. Requests over the limit wait in a bounded queue, and are rejected with a BulkheadError
+// when the queue is full or the queue timeout expires. In adaptive mode, the limit follows the
+// health of the dependency.
+//
+// Example usage:
+//
+//	client := fastshot.NewClient("https://api.example.com").
+//		Bulkhead().Enable(fastshot.BulkheadConfig{
+//			MaxConcurrent:    50,
+//			MaxQueue:         100,
+//			QueueTimeout:     time.Second,
+//			Adaptive:         true,
+//			LatencyThreshold: 500 * time.Millisecond,
+//		}).
+//		Build()
+type BuilderBulkhead[T any] interface {
+	Enable(config BulkheadConfig) *T
+	SetCustom(bulkhead *Bulkhead) *T
+}
+
+// BuilderCoalescing is the interface that wraps the basic methods for configuring request coalescing.
+//
+// Coalescing merges concurrent identical GET and HEAD requests, i.e. with the same full URL and
+// values of the vary headers, into a single call. Load-balanced clients match the path and query,
+// the hash key and the client instead of the full URL. The response is buffered and shared, so each
+// caller reads its own copy of the body. Waiting requests are not sent, so their hooks do not run.
+// Vary headers are matched against the client and request headers and cookies; headers set by
+// before-request hooks are not part of the key. The shared call is canceled only once every
+// caller has left, so a caller that gives up does not fail the others.
+//
+// Example usage:
+//
+//	client := fastshot.NewClient("https://config.example.com").
+//		Coalescing().Enable(fastshot.CoalescingConfig{
+//			VaryHeaders: []string{"Authorization", "Accept"},
+//		}).
+//		Build()
+type BuilderCoalescing[T any] interface {
+	Enable(config CoalescingConfig) *T
+	SetCustom(coalescer *Coalescer) *T
+}
+
 // BuilderHttpClientConfig is the interface that wraps the basic methods for configuring the HTTP client.
 //
 // This interface is crucial for fine-tuning the behavior of the underlying HTTP client.
@@ -397,6 +670,49 @@ type BuilderRequestQuery[T any] interface {
 	SetRawString(query string) *T
 }
 
+// BuilderRequestLoadBalancer is the interface that wraps the basic methods for steering a request
+// on load-balanced clients.
+//
+// Example usage:
+//
+//	client := fastshot.NewClientLoadBalancer([]string{
+//		"https://api1.example.com",
+//		"https://api2.example.com",
+//	}).
+//		LoadBalancer().WithStrategy(fastshot.NewConsistentHashStrategy()).
+//		Build()
+//
+//	response, err := client.GET("/reports").
+//		LoadBalancer().WithHashKey(tenantID).
+//		Send()
+//
+// Requests with the same hash key land on the same base URL as long as the set of base URLs
+// does not change. The key is ignored by strategies that do not hash.
+type BuilderRequestLoadBalancer[T any] interface {
+	WithHashKey(key string) *T
+}
+
+// BuilderRequestHedge is the interface that wraps the basic methods for hedging a request.
+//
+// Hedging trades extra load for lower tail latency: when no response arrives within the hedge
+// delay, a duplicate attempt is sent, preferably to another base URL of load-balanced clients,
+// and the first response wins. The other attempts are canceled and their responses closed.
+//
+// Example usage:
+//
+//	tracker := fastshot.NewLatencyTracker(200, 50*time.Millisecond)
+//
+//	response, err := client.GET("/search").
+//		Hedge().SetPercentileDelay(tracker, 95, 1).
+//		Send()
+//
+// Only idempotent methods can be hedged; hedging any other method records a validation error.
+// Hooks run for every attempt, possibly concurrently.
+type BuilderRequestHedge[T any] interface {
+	SetFixedDelay(delay time.Duration, maxHedges uint) *T
+	SetPercentileDelay(tracker *LatencyTracker, percentile float64, maxHedges uint) *T
+}
+
 // BuilderRequestRetry is the interface that wraps the basic methods for configuring request retries.
 //
 // Retry functionality is crucial for building robust HTTP clients that can handle transient
@@ -416,6 +732,10 @@ type BuilderRequestQuery[T any] interface {
 //			}).
 //			Send()
 //
+// The generic type parameter T allows this interface to be used with both ClientBuilder and
+// RequestBuilder. A policy set on the client is the default for every request, which can still
+// override it or turn it off with Disable.
+//
 // The flexibility in retry configuration allows users to fine-tune the retry behavior
 // to their specific needs, improving the reliability of their HTTP requests.
 type BuilderRequestRetry[T any] interface {
@@ -423,8 +743,60 @@ type BuilderRequestRetry[T any] interface {
 	SetConstantBackoffWithJitter(interval time.Duration, maxAttempts uint) *T
 	SetExponentialBackoff(interval time.Duration, maxAttempts uint, backoffRate float64) *T
 	SetExponentialBackoffWithJitter(interval time.Duration, maxAttempts uint, backoffRate float64) *T
+	SetBackoffStrategy(strategy BackoffStrategy, maxAttempts uint) *T
 	WithRetryCondition(shouldRetry func(response *Response) bool) *T
+	WithRetryPredicate(predicate RetryPredicate) *T
 	WithMaxDelay(duration time.Duration) *T
+	WithMaxElapsedTime(duration time.Duration) *T
+	WithPerAttemptTimeout(duration time.Duration) *T
+	WithRetryAfter() *T
+	WithFailover() *T
+	Disable() *T
+}
+
+// BackoffStrategy is the interface that wraps the basic method for computing retry delays.
+//
+// The retry engine asks the strategy for the delay to wait after each failed attempt, passing
+// the attempt index, the previous delay and the configured maximum delay. Strategies that need
+// state across attempts, such as decorrelated jitter, derive it from the previous delay, so a
+// single strategy value can be safely shared by concurrent requests.
+//
+// Example usage:
+//
+//	response, err := client.GET("/users").
+//		Retry().SetBackoffStrategy(fastshot.NewDecorrelatedJitterBackoff(100*time.Millisecond), 5).
+//		Retry().WithMaxDelay(5 * time.Second).
+//		Send()
+//
+// Built-in strategies include constant, linear, exponential and Fibonacci schedules, and the
+// full, equal and decorrelated jitter variants.
+type BackoffStrategy interface {
+	NextDelay(state BackoffState) time.Duration
+}
+
+// LoadBalancingStrategy is the interface that wraps the basic methods for balancing load across base URLs.
+//
+// Select returns the index of the backend to use for the next attempt. The backends passed to
+// Select have already been narrowed by the balancer, e.g. to skip backends excluded by failover,
+// so strategies only decide among them. Start and Finish are called when a request to one of the
+// backends starts and finishes, which lets strategies track in-flight requests. Strategies are
+// shared by every request of a client and must be safe for concurrent use.
+//
+// Example usage:
+//
+//	client := fastshot.NewClientLoadBalancer([]string{
+//		"https://api1.example.com",
+//		"https://api2.example.com",
+//	}).
+//		LoadBalancer().WithStrategy(fastshot.NewLeastOutstandingStrategy()).
+//		Build()
+//
+// Built-in strategies include round-robin, smooth weighted round-robin, least outstanding
+// requests and power of two choices.
+type LoadBalancingStrategy interface {
+	Select(backends []Backend, criteria BaseURLCriteria) int
+	Start(baseURL *url.URL)
+	Finish(baseURL *url.URL)
 }
 
 // HeaderWrapper is the interface that wraps the basic methods for managing HTTP headers.
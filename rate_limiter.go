@@ -0,0 +1,340 @@
+package fastshot
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opus-domini/fast-shot/constant/header"
+)
+
+// maxRateLimitHosts is the number of per-host limiters kept. Beyond it, the least recently used
+// one is evicted; a host that comes back starts with a full bucket.
+const maxRateLimitHosts = 1024
+
+type (
+	// RateLimiter is a token bucket that lets requests through at a steady rate, with bursts of
+	// up to burst requests. It is safe for concurrent use by every request of a client.
+	RateLimiter struct {
+		mu     sync.Mutex
+		limit  float64
+		rate   float64
+		burst  float64
+		tokens float64
+		last   time.Time
+		now    func() time.Time
+	}
+
+	// RateLimits holds the rate limiters applied to the requests of a client: one shared by every
+	// request, one per host and one per path prefix. A request waits for a token from each
+	// limiter that applies to it. It is safe for concurrent use by every request of a client.
+	RateLimits struct {
+		mu       sync.Mutex
+		global   *RateLimiter
+		perHost  *rateLimitSpec
+		hosts    map[string]*hostRateLimiter
+		uses     uint64
+		prefixes []rateLimitPrefix
+		adaptive bool
+	}
+
+	// hostRateLimiter is the limiter of a host, with the time it was last used as a use count.
+	hostRateLimiter struct {
+		limiter *RateLimiter
+		used    uint64
+	}
+
+	// rateLimitSpec is the rate and burst of the limiters created for each host.
+	rateLimitSpec struct {
+		rate  float64
+		burst uint
+	}
+
+	// rateLimitPrefix is the limiter of the requests whose path starts with the prefix.
+	rateLimitPrefix struct {
+		prefix  string
+		limiter *RateLimiter
+	}
+)
+
+// NewRateLimiter creates a RateLimiter that allows rate requests per second, with bursts of up to
+// burst requests. The rate must be positive. The bucket starts full. A zero burst is treated as 1.
+func NewRateLimiter(rate float64, burst uint) *RateLimiter {
+	now := time.Now
+	return &RateLimiter{
+		limit:  rate,
+		rate:   rate,
+		burst:  float64(max(burst, 1)),
+		tokens: float64(max(burst, 1)),
+		last:   now(),
+		now:    now,
+	}
+}
+
+// Rate returns the current rate in requests per second. It is lower than the configured rate
+// while adapted to the rate limit headers of the server.
+func (l *RateLimiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// SetRate changes the rate, in requests per second. Rates that are not positive are ignored.
+func (l *RateLimiter) SetRate(rate float64) {
+	if rate <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.advance(l.now())
+	l.limit, l.rate = rate, rate
+}
+
+// Wait blocks until a token is available or the context is done. It fails at once when the
+// context deadline comes before the token.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	l.advance(now)
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+		l.tokens++
+		l.mu.Unlock()
+		return context.DeadlineExceeded
+	}
+	l.mu.Unlock()
+
+	if err := waitForRetry(ctx, delay); err != nil {
+		// Give the reserved token back
+		l.refund()
+		return err
+	}
+	return nil
+}
+
+// refund gives back a token taken by Wait for a request that is not sent.
+func (l *RateLimiter) refund() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.burst, l.tokens+1)
+}
+
+// adapt lowers the rate to what the rate limit headers of the response allow, and pauses the
+// limiter until the reset when the server reports no remaining requests. Responses without
+// such headers double the rate back toward the configured one.
+func (l *RateLimiter) adapt(response *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.advance(now)
+
+	rate, adapted := l.limit, false
+	if quota, window, ok := parseRateLimitPolicy(response.Header.Get(header.RateLimitPolicy.String())); ok {
+		rate, adapted = min(rate, quota/window.Seconds()), true
+	}
+
+	reset, hasReset := retryAfterDelay(response.Header, now)
+	remaining, hasRemaining := rateLimitRemaining(response.Header)
+	switch {
+	case hasReset && (response.StatusCode == http.StatusTooManyRequests || hasRemaining && remaining == 0):
+		// Pause until the reset: the next token is only available then
+		if adapted && rate > 0 {
+			l.rate = rate
+		}
+		l.tokens = min(l.tokens, 1-reset.Seconds()*l.rate)
+		return
+	case hasReset && hasRemaining && reset > 0:
+		rate, adapted = min(rate, remaining/reset.Seconds()), true
+	}
+	switch {
+	case adapted && rate > 0:
+		l.rate = rate
+	case !adapted && !hasRemaining:
+		l.rate = min(l.limit, l.rate*2)
+	}
+}
+
+// advance refills the bucket with the tokens earned since the last update. The caller must hold the lock.
+func (l *RateLimiter) advance(now time.Time) {
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.tokens = min(l.burst, l.tokens+elapsed*l.rate)
+		l.last = now
+	}
+}
+
+// NewRateLimits creates an empty RateLimits.
+func NewRateLimits() *RateLimits {
+	return &RateLimits{hosts: make(map[string]*hostRateLimiter)}
+}
+
+// Limiter returns the limiter shared by every request, if any.
+func (r *RateLimits) Limiter() *RateLimiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.global
+}
+
+// SetLimiter sets the limiter shared by every request.
+func (r *RateLimits) SetLimiter(limiter *RateLimiter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.global = limiter
+}
+
+// SetPerHost gives each host its own limiter with the given rate and burst. The limiters of the
+// 1024 most recently used hosts are kept.
+func (r *RateLimits) SetPerHost(rate float64, burst uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.perHost = &rateLimitSpec{rate: rate, burst: burst}
+	clear(r.hosts)
+}
+
+// SetPathPrefix sets the limiter of the requests whose path starts with the prefix. When several
+// prefixes match, the longest one applies.
+func (r *RateLimits) SetPathPrefix(prefix string, limiter *RateLimiter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prefixes = append(r.prefixes, rateLimitPrefix{prefix: prefix, limiter: limiter})
+}
+
+// SetAdaptive enables the adaptation of the rate to the rate limit headers of the responses.
+func (r *RateLimits) SetAdaptive(adaptive bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.adaptive = adaptive
+}
+
+// Wait blocks until every limiter that applies to the URL gives a token, or the context is done.
+// When a limiter fails, the tokens already taken from the others are given back.
+func (r *RateLimits) Wait(ctx context.Context, target *url.URL) error {
+	limiters := r.limiters(target)
+	for index, limiter := range limiters {
+		if err := limiter.Wait(ctx); err != nil {
+			for _, taken := range limiters[:index] {
+				taken.refund()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Observe adapts the most specific limiter that applies to the URL to the rate limit headers of
+// the response, when adaptation is enabled.
+func (r *RateLimits) Observe(target *url.URL, response *http.Response) {
+	r.mu.Lock()
+	adaptive := r.adaptive
+	r.mu.Unlock()
+	if !adaptive || response == nil {
+		return
+	}
+	if limiters := r.limiters(target); len(limiters) > 0 {
+		limiters[len(limiters)-1].adapt(response)
+	}
+}
+
+// limiters returns the limiters that apply to the URL, from the most general to the most specific.
+func (r *RateLimits) limiters(target *url.URL) []*RateLimiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var limiters []*RateLimiter
+	if r.global != nil {
+		limiters = append(limiters, r.global)
+	}
+	if r.perHost != nil {
+		limiters = append(limiters, r.hostLimiter(target.Host))
+	}
+	var longest *rateLimitPrefix
+	for index, prefix := range r.prefixes {
+		if strings.HasPrefix(target.Path, prefix.prefix) && (longest == nil || len(prefix.prefix) > len(longest.prefix)) {
+			longest = &r.prefixes[index]
+		}
+	}
+	if longest != nil {
+		limiters = append(limiters, longest.limiter)
+	}
+	return limiters
+}
+
+// hostLimiter returns the limiter of the host, creating it when needed and evicting the least
+// recently used one beyond maxRateLimitHosts. The caller must hold the lock.
+func (r *RateLimits) hostLimiter(host string) *RateLimiter {
+	r.uses++
+	if entry, ok := r.hosts[host]; ok {
+		entry.used = r.uses
+		return entry.limiter
+	}
+	if len(r.hosts) >= maxRateLimitHosts {
+		oldest, used := "", uint64(math.MaxUint64)
+		for name, entry := range r.hosts {
+			if entry.used < used {
+				oldest, used = name, entry.used
+			}
+		}
+		delete(r.hosts, oldest)
+	}
+	limiter := NewRateLimiter(r.perHost.rate, r.perHost.burst)
+	r.hosts[host] = &hostRateLimiter{limiter: limiter, used: r.uses}
+	return limiter
+}
+
+// parseRateLimitPolicy parses the quota and window of the first policy of a RateLimit-Policy
+// header, such as "100;w=60" or `"default";q=100;w=60`. The window defaults to one second.
+func parseRateLimitPolicy(value string) (float64, time.Duration, bool) {
+	policy, _, _ := strings.Cut(value, ",")
+	quota, window := -1.0, time.Second
+	for index, part := range strings.Split(policy, ";") {
+		key, raw, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			if index == 0 {
+				raw = key
+				key = "q"
+			} else {
+				continue
+			}
+		}
+		number, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "q":
+			quota = number
+		case "w":
+			window = time.Duration(number * float64(time.Second))
+		}
+	}
+	if quota < 0 || window <= 0 {
+		return 0, 0, false
+	}
+	return quota, window, true
+}
+
+// rateLimitRemaining returns the number of requests left in the current window, from the
+// RateLimit-Remaining or X-RateLimit-Remaining headers.
+func rateLimitRemaining(httpHeader http.Header) (float64, bool) {
+	for _, name := range []header.Type{header.RateLimitRemaining, header.XRateLimitRemaining} {
+		if value := strings.TrimSpace(httpHeader.Get(name.String())); value != "" {
+			if remaining, err := strconv.ParseFloat(value, 64); err == nil && remaining >= 0 {
+				return remaining, true
+			}
+		}
+	}
+	return 0, false
+}
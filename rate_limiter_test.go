@@ -0,0 +1,310 @@
+package fastshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	// Arrange
+	limiter := NewRateLimiter(50, 2)
+	start := time.Now()
+
+	// Act
+	for range 4 {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	// Assert
+	if elapsed < 30*time.Millisecond {
+		t.Errorf("elapsed got %v, want about 40ms for two tokens at 50/s", elapsed)
+	}
+}
+
+func TestRateLimiter_WaitContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      func() (context.Context, context.CancelFunc)
+		expected error
+	}{
+		{
+			name: "Deadline before the next token",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+			expected: context.DeadlineExceeded,
+		},
+		{
+			name: "Canceled while waiting",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(10*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			expected: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			limiter := NewRateLimiter(1, 1)
+			_ = limiter.Wait(context.Background())
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			// Act
+			err := limiter.Wait(ctx)
+
+			// Assert
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("error got %v, want %v", err, tt.expected)
+			}
+			limiter.mu.Lock()
+			defer limiter.mu.Unlock()
+			if limiter.tokens < -0.1 {
+				t.Errorf("tokens got %v, want the reserved token back", limiter.tokens)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_Adapt(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		headers        map[string]string
+		adaptedRate    float64
+		expectedRate   float64
+		expectedTokens float64
+	}{
+		{
+			name:           "No rate limit headers",
+			status:         http.StatusOK,
+			expectedRate:   10,
+			expectedTokens: 5,
+		},
+		{
+			name:           "No rate limit headers recovers the rate",
+			status:         http.StatusOK,
+			adaptedRate:    2,
+			expectedRate:   4,
+			expectedTokens: 5,
+		},
+		{
+			name:           "Recovery stops at the configured rate",
+			status:         http.StatusOK,
+			adaptedRate:    8,
+			expectedRate:   10,
+			expectedTokens: 5,
+		},
+		{
+			name:           "Policy below the configured rate",
+			status:         http.StatusOK,
+			headers:        map[string]string{"RateLimit-Policy": "120;w=60"},
+			expectedRate:   2,
+			expectedTokens: 5,
+		},
+		{
+			name:           "Policy above the configured rate",
+			status:         http.StatusOK,
+			headers:        map[string]string{"RateLimit-Policy": `"burst";q=1000;w=1`},
+			expectedRate:   10,
+			expectedTokens: 5,
+		},
+		{
+			name:           "Remaining requests spread until the reset",
+			status:         http.StatusOK,
+			headers:        map[string]string{"X-RateLimit-Remaining": "20", "X-RateLimit-Reset": "10"},
+			expectedRate:   2,
+			expectedTokens: 5,
+		},
+		{
+			name:           "No remaining requests pauses until the reset",
+			status:         http.StatusOK,
+			headers:        map[string]string{"RateLimit-Remaining": "0", "RateLimit-Reset": "2"},
+			expectedRate:   10,
+			expectedTokens: -19,
+		},
+		{
+			name:           "Too many requests pauses until Retry-After",
+			status:         http.StatusTooManyRequests,
+			headers:        map[string]string{"Retry-After": "1"},
+			expectedRate:   10,
+			expectedTokens: -9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			now := time.Unix(1_700_000_000, 0)
+			limiter := NewRateLimiter(10, 5)
+			limiter.now = func() time.Time { return now }
+			limiter.last = now
+			if tt.adaptedRate > 0 {
+				limiter.rate = tt.adaptedRate
+			}
+			response := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for key, value := range tt.headers {
+				response.Header.Set(key, value)
+			}
+
+			// Act
+			limiter.adapt(response)
+
+			// Assert
+			if got := limiter.Rate(); math.Abs(got-tt.expectedRate) > 1e-9 {
+				t.Errorf("rate got %v, want %v", got, tt.expectedRate)
+			}
+			if got := limiter.tokens; math.Abs(got-tt.expectedTokens) > 1e-9 {
+				t.Errorf("tokens got %v, want %v", got, tt.expectedTokens)
+			}
+		})
+	}
+}
+
+func TestParseRateLimitPolicy(t *testing.T) {
+	tests := []struct {
+		value          string
+		expectedQuota  float64
+		expectedWindow time.Duration
+		expectedOK     bool
+	}{
+		{value: "100;w=60", expectedQuota: 100, expectedWindow: time.Minute, expectedOK: true},
+		{value: `"default";q=100;w=60, "daily";q=1000;w=86400`, expectedQuota: 100, expectedWindow: time.Minute, expectedOK: true},
+		{value: "10", expectedQuota: 10, expectedWindow: time.Second, expectedOK: true},
+		{value: "", expectedOK: false},
+		{value: "q=10;w=0", expectedOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			// Act
+			quota, window, ok := parseRateLimitPolicy(tt.value)
+
+			// Assert
+			if ok != tt.expectedOK {
+				t.Fatalf("ok got %v, want %v", ok, tt.expectedOK)
+			}
+			if quota != tt.expectedQuota || window != tt.expectedWindow {
+				t.Errorf("got %v per %v, want %v per %v", quota, window, tt.expectedQuota, tt.expectedWindow)
+			}
+		})
+	}
+}
+
+func TestRateLimits_Limiters(t *testing.T) {
+	// Arrange
+	global := NewRateLimiter(100, 1)
+	api := NewRateLimiter(10, 1)
+	search := NewRateLimiter(1, 1)
+	limits := NewRateLimits()
+	limits.SetLimiter(global)
+	limits.SetPerHost(50, 1)
+	limits.SetPathPrefix("/api", api)
+	limits.SetPathPrefix("/api/search", search)
+
+	tests := []struct {
+		name     string
+		target   string
+		expected []*RateLimiter
+	}{
+		{name: "Longest prefix", target: "https://a.com/api/search?q=go", expected: []*RateLimiter{global, nil, search}},
+		{name: "Shorter prefix", target: "https://a.com/api/users", expected: []*RateLimiter{global, nil, api}},
+		{name: "No prefix", target: "https://b.com/health", expected: []*RateLimiter{global, nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			target, _ := url.Parse(tt.target)
+
+			// Act
+			got := limits.limiters(target)
+
+			// Assert
+			if len(got) != len(tt.expected) {
+				t.Fatalf("limiters got %d, want %d", len(got), len(tt.expected))
+			}
+			for index, want := range tt.expected {
+				if want != nil && got[index] != want {
+					t.Errorf("limiter %d got %p, want %p", index, got[index], want)
+				}
+			}
+			if host := limits.limiters(target)[1]; host != got[1] {
+				t.Error("got a new host limiter, want the same one")
+			}
+		})
+	}
+
+	a, _ := url.Parse("https://a.com/")
+	b, _ := url.Parse("https://b.com/")
+	if limits.limiters(a)[1] == limits.limiters(b)[1] {
+		t.Error("got the same limiter for two hosts, want one per host")
+	}
+}
+
+func TestRateLimits_WaitRefund(t *testing.T) {
+	// Arrange
+	global := NewRateLimiter(100, 1)
+	search := NewRateLimiter(1, 1)
+	_ = search.Wait(context.Background())
+	limits := NewRateLimits()
+	limits.SetLimiter(global)
+	limits.SetPathPrefix("/search", search)
+	target, _ := url.Parse("https://a.com/search")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Act
+	err := limits.Wait(ctx, target)
+
+	// Assert
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error got %v, want %v", err, context.DeadlineExceeded)
+	}
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	if global.tokens < 0.99 {
+		t.Errorf("global tokens got %v, want the taken token back", global.tokens)
+	}
+}
+
+func TestRateLimits_HostEviction(t *testing.T) {
+	// Arrange
+	limits := NewRateLimits()
+	limits.SetPerHost(10, 1)
+	host := func(index int) *url.URL {
+		return &url.URL{Scheme: "https", Host: fmt.Sprintf("10.0.%d.%d", index/256, index%256)}
+	}
+	first := limits.limiters(host(0))[0]
+	for index := 1; index < maxRateLimitHosts; index++ {
+		limits.limiters(host(index))
+	}
+	// Use the first host again, so the second one is the least recently used
+	limits.limiters(host(0))
+
+	// Act
+	limits.limiters(host(maxRateLimitHosts))
+
+	// Assert
+	if got := len(limits.hosts); got != maxRateLimitHosts {
+		t.Errorf("host limiters got %d, want %d", got, maxRateLimitHosts)
+	}
+	if _, ok := limits.hosts[host(1).Host]; ok {
+		t.Error("least recently used host was kept, want it evicted")
+	}
+	if got := limits.limiters(host(0))[0]; got != first {
+		t.Error("recently used host got a new limiter, want the same one")
+	}
+}
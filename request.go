@@ -1,12 +1,14 @@
 package fastshot
 
 import (
+	"cmp"
+	"context"
 	"errors"
 	"fmt"
-	"math"
-	"math/rand/v2"
+	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"time"
 
 	"github.com/opus-domini/fast-shot/constant"
@@ -23,17 +25,28 @@ type RequestBuilder struct {
 }
 
 func newRequest(client ClientConfig, method method.Type, path string) *RequestBuilder {
+	config := newRequestConfigBase(method, path)
+
+	// Inherit the client default retry policy
+	if retry, ok := client.(ConfigRetry); ok && retry.RetryConfig() != nil {
+		config.retryConfig = retry.RetryConfig().clone()
+	}
+
 	return &RequestBuilder{
 		request: &Request{
 			client: client,
-			config: newRequestConfigBase(method, path),
+			config: config,
 		},
 	}
 }
 
 func (b *RequestBuilder) createFullURL() *url.URL {
+	return b.createFullURLFor(b.request.client.BaseURL())
+}
+
+func (b *RequestBuilder) createFullURLFor(baseURL *url.URL) *url.URL {
 	// Parse base URL and path
-	fullURL := b.request.client.BaseURL().JoinPath(b.request.config.Path())
+	fullURL := baseURL.JoinPath(b.request.config.Path())
 
 	// Add query params
 	query := fullURL.Query()
@@ -48,15 +61,24 @@ func (b *RequestBuilder) createFullURL() *url.URL {
 }
 
 func (b *RequestBuilder) createHTTPRequest() (*http.Request, error) {
+	return b.createHTTPRequestFor(b.request.config.Context().Unwrap(), b.request.client.BaseURL())
+}
+
+func (b *RequestBuilder) createHTTPRequestFor(ctx context.Context, baseURL *url.URL) (*http.Request, error) {
 	// Create full URL
-	fullURL := b.createFullURL()
+	fullURL := b.createFullURLFor(baseURL)
 
+	return b.newHTTPRequest(ctx, fullURL.String(), b.request.config.Body().Unwrap())
+}
+
+// newHTTPRequest creates an HTTP request with the client and request cookies and headers.
+func (b *RequestBuilder) newHTTPRequest(ctx context.Context, fullURL string, body io.Reader) (*http.Request, error) {
 	// Create Http Request with context
 	request, err := http.NewRequestWithContext(
-		b.request.config.Context().Unwrap(),
+		ctx,
 		b.request.config.Method().String(),
-		fullURL.String(),
-		b.request.config.Body().Unwrap(),
+		fullURL,
+		body,
 	)
 	if err != nil {
 		return nil, err
@@ -114,7 +136,84 @@ func (b *RequestBuilder) runAfterResponseHooks(req *http.Request, resp *http.Res
 	}
 }
 
-func (b *RequestBuilder) execute(request *http.Request) (*Response, error) {
+// retryBudget returns the client retry budget, if any.
+func (b *RequestBuilder) retryBudget() *RetryBudget {
+	if config, ok := b.request.client.(ConfigRetry); ok {
+		return config.RetryBudget()
+	}
+	return nil
+}
+
+// circuitBreaker returns the client circuit breaker, if any.
+func (b *RequestBuilder) circuitBreaker() *CircuitBreaker {
+	if config, ok := b.request.client.(ConfigCircuitBreaker); ok {
+		return config.CircuitBreaker()
+	}
+	return nil
+}
+
+// rateLimits returns the client rate limits, if any.
+func (b *RequestBuilder) rateLimits() *RateLimits {
+	if config, ok := b.request.client.(ConfigRateLimits); ok {
+		return config.RateLimits()
+	}
+	return nil
+}
+
+// bulkhead returns the client bulkhead, if any.
+func (b *RequestBuilder) bulkhead() *Bulkhead {
+	if config, ok := b.request.client.(ConfigBulkhead); ok {
+		return config.Bulkhead()
+	}
+	return nil
+}
+
+// coalescer returns the client coalescer, if any.
+func (b *RequestBuilder) coalescer() *Coalescer {
+	if config, ok := b.request.client.(ConfigCoalescer); ok {
+		return config.Coalescer()
+	}
+	return nil
+}
+
+func (b *RequestBuilder) runRetryHooks(req *http.Request, event RetryEvent) {
+	if hooks, ok := b.request.client.(ConfigLifecycleHooks); ok {
+		for _, hook := range hooks.RetryHooks() {
+			hook(req, event)
+		}
+	}
+	for _, hook := range b.request.config.RetryHooks() {
+		hook(req, event)
+	}
+}
+
+func (b *RequestBuilder) runErrorHooks(req *http.Request, err error) {
+	if hooks, ok := b.request.client.(ConfigLifecycleHooks); ok {
+		for _, hook := range hooks.ErrorHooks() {
+			hook(req, err)
+		}
+	}
+	for _, hook := range b.request.config.ErrorHooks() {
+		hook(req, err)
+	}
+}
+
+func (b *RequestBuilder) runGiveUpHooks(req *http.Request, err error) {
+	// Nothing was sent when the request could not be created
+	if req == nil {
+		return
+	}
+	if hooks, ok := b.request.client.(ConfigLifecycleHooks); ok {
+		for _, hook := range hooks.GiveUpHooks() {
+			hook(req, err)
+		}
+	}
+	for _, hook := range b.request.config.GiveUpHooks() {
+		hook(req, err)
+	}
+}
+
+func (b *RequestBuilder) execute(request *http.Request, record func(*http.Response, error)) (*Response, error) {
 	// Run before-request hooks
 	if err := b.runBeforeRequestHooks(request); err != nil {
 		return nil, errors.Join(errors.New(constant.ErrMsgBeforeRequestHook), err)
@@ -123,7 +222,12 @@ func (b *RequestBuilder) execute(request *http.Request) (*Response, error) {
 	// Execute request
 	//nolint:bodyclose // Response body is closed by the caller via Response APIs.
 	response, err := b.request.client.HttpClient().Do(request)
+	record(response, err)
 	if err != nil {
+		// Hedged attempts canceled because another one won did not fail
+		if !errors.Is(context.Cause(request.Context()), errHedgeLost) {
+			b.runErrorHooks(request, err)
+		}
 		return nil, err
 	}
 
@@ -133,50 +237,413 @@ func (b *RequestBuilder) execute(request *http.Request) (*Response, error) {
 	return newResponse(response), nil
 }
 
-func (b *RequestBuilder) executeWithRetry(req *http.Request) (*Response, error) {
+// attemptResult holds what a single attempt sent and received. The request is nil when it could
+// not be created, and sent is false when the attempt was rejected before being executed, e.g. by an
+// open circuit breaker; such attempts are not retried.
+type attemptResult struct {
+	baseURL  *url.URL
+	request  *http.Request
+	response *Response
+	err      error
+	sent     bool
+}
+
+// baseURLCriteria returns the criteria of the first attempt. The hash key is the one set on the
+// request, or else the one the client derives from the request headers, cookies and query.
+func (b *RequestBuilder) baseURLCriteria() BaseURLCriteria {
+	if key := b.request.config.HashKey(); key != "" {
+		return BaseURLCriteria{HashKey: key}
+	}
+
+	provider, ok := b.request.client.(HashKeyProvider)
+	if !ok || provider.HashKeyFunc() == nil {
+		return BaseURLCriteria{}
+	}
+	req, err := b.newHTTPRequest(b.request.config.Context().Unwrap(), b.createFullURLFor(&url.URL{}).String(), nil)
+	if err != nil {
+		return BaseURLCriteria{}
+	}
+	return BaseURLCriteria{HashKey: provider.HashKeyFunc()(req)}
+}
+
+// selectBaseURL resolves the base URL for an attempt, honoring the criteria when the client supports it.
+func (b *RequestBuilder) selectBaseURL(criteria BaseURLCriteria) *url.URL {
+	if selector, ok := b.request.client.(BaseURLSelector); ok {
+		return selector.SelectBaseURL(criteria)
+	}
+	return b.request.client.BaseURL()
+}
+
+// admitBaseURL resolves the base URL for an attempt and asks the circuit breaker, if any, to let
+// the request through. Base URLs with an open circuit are excluded in turn, so load-balanced
+// clients fall back to the other ones. It returns ErrCircuitOpen when every circuit is open, or
+// else the function that records the outcome of the request, if any.
+func (b *RequestBuilder) admitBaseURL(criteria BaseURLCriteria) (*url.URL, func(*http.Response, error), error) {
+	baseURL := b.selectBaseURL(criteria)
+	breaker := b.circuitBreaker()
+	if baseURL == nil || breaker == nil {
+		return baseURL, nil, nil
+	}
+
+	for {
+		record, err := breaker.Allow(baseURL.String())
+		if err == nil {
+			return baseURL, record, nil
+		}
+		if criteria.excludes(baseURL) {
+			return baseURL, nil, fmt.Errorf("%w: %s", err, baseURL)
+		}
+		criteria.Exclude = append(slices.Clip(criteria.Exclude), baseURL)
+		baseURL = b.selectBaseURL(criteria)
+	}
+}
+
+// executeAttempt creates a fresh request and executes it, bounded by the per-attempt timeout when set.
+// The returned request is nil when it could not be created.
+func (b *RequestBuilder) executeAttempt(ctx context.Context, criteria BaseURLCriteria) attemptResult {
+	baseURL, admitted, err := b.admitBaseURL(criteria)
+	if baseURL == nil {
+		return attemptResult{err: errors.Join(errors.New(constant.ErrMsgCreateRequest), errors.New(constant.ErrMsgEmptyBaseURL))}
+	}
+	if err != nil {
+		return b.rejectedAttempt(ctx, baseURL, err)
+	}
+	return b.executeAdmitted(ctx, baseURL, admitted)
+}
+
+// rejectedAttempt returns the result of an attempt rejected before it was sent, with the request
+// that would have been sent, if it can be created, so give-up hooks can tell which one failed.
+func (b *RequestBuilder) rejectedAttempt(ctx context.Context, baseURL *url.URL, err error) attemptResult {
+	req, errCreate := b.createHTTPRequestFor(ctx, baseURL)
+	if errCreate != nil {
+		req = nil
+	}
+	return attemptResult{baseURL: baseURL, request: req, err: err}
+}
+
+// executeAdmitted executes an attempt against a base URL the circuit breaker, if any, has admitted.
+func (b *RequestBuilder) executeAdmitted(ctx context.Context, baseURL *url.URL, admitted func(*http.Response, error)) attemptResult {
+	if admitted != nil {
+		// Release the circuit breaker admission if the request is not sent
+		defer admitted(nil, nil)
+	}
+	req, err := b.createHTTPRequestFor(ctx, baseURL)
+	if err != nil {
+		return attemptResult{baseURL: baseURL, err: errors.Join(errors.New(constant.ErrMsgCreateRequest), err)}
+	}
+	// Wait for the rate limiters, outside of the per-attempt deadline
+	limits := b.rateLimits()
+	if limits != nil {
+		if err := limits.Wait(req.Context(), req.URL); err != nil {
+			return attemptResult{baseURL: baseURL, request: req, err: errors.Join(errors.New(constant.ErrMsgRateLimitWait), err)}
+		}
+	}
+
+	// Collect what must be released once the attempt is over: the bulkhead slot, the load
+	// balancer bookkeeping and the per-attempt deadline. All last until the response body is closed.
+	var releases []func()
+	bulkhead := b.bulkhead()
+	if bulkhead != nil {
+		release, err := bulkhead.Acquire(req.Context())
+		if err != nil {
+			return attemptResult{baseURL: baseURL, request: req, err: err}
+		}
+		releases = append(releases, release)
+	}
+	observer, _ := b.request.client.(BaseURLObserver)
+	if observer != nil {
+		if finished := observer.RequestStarted(baseURL); finished != nil {
+			releases = append(releases, finished)
+		}
+	}
+	if config := b.request.config.RetryConfig(); config != nil && config.PerAttemptTimeout() != nil {
+		ctx, cancel := context.WithTimeout(req.Context(), *config.PerAttemptTimeout())
+		req = req.WithContext(ctx)
+		releases = append(releases, cancel)
+	}
+
+	started := time.Now()
+	response, err := b.execute(req, func(response *http.Response, err error) {
+		if observer != nil {
+			observer.RecordOutcome(baseURL, response, err)
+		}
+		if admitted != nil {
+			admitted(response, err)
+		}
+		if limits != nil {
+			limits.Observe(req.URL, response)
+		}
+		if bulkhead != nil {
+			bulkhead.Record(started, response, err)
+		}
+	})
+	if len(releases) == 0 {
+		return attemptResult{baseURL: baseURL, request: req, response: response, err: err, sent: true}
+	}
+
+	release := func() {
+		for _, fn := range releases {
+			fn()
+		}
+	}
+	if err != nil {
+		release()
+		return attemptResult{baseURL: baseURL, request: req, err: err, sent: true}
+	}
+	response.onBodyClose(release)
+
+	return attemptResult{baseURL: baseURL, request: req, response: response, sent: true}
+}
+
+// executeHedgedAttempt executes an attempt, hedging it when enabled: each time no response arrives
+// within the hedge delay, a duplicate attempt is sent, preferably to a base URL not tried yet, up to
+// the maximum number of hedges. The first response wins, and the other attempts are canceled and
+// their responses closed. The result is the last failure when every attempt fails.
+func (b *RequestBuilder) executeHedgedAttempt(ctx context.Context, criteria BaseURLCriteria) attemptResult {
+	config := b.request.config.HedgeConfig()
+	if config == nil || config.MaxHedges == 0 {
+		return b.executeAttempt(ctx, criteria)
+	}
+
+	type hedgedResult struct {
+		attemptResult
+		index int
+	}
+	results := make(chan hedgedResult, config.MaxHedges+1)
+	var cancels []context.CancelCauseFunc
+	var starts []time.Time
+	// Launch an attempt, excluding its base URL from the next ones
+	launch := func() *attemptResult {
+		baseURL, admitted, err := b.admitBaseURL(criteria)
+		if baseURL == nil {
+			return &attemptResult{err: errors.Join(errors.New(constant.ErrMsgCreateRequest), errors.New(constant.ErrMsgEmptyBaseURL))}
+		}
+		if err != nil {
+			rejected := b.rejectedAttempt(ctx, baseURL, err)
+			return &rejected
+		}
+		criteria.Exclude = append(slices.Clip(criteria.Exclude), baseURL)
+		attemptCtx, cancel := context.WithCancelCause(ctx)
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+		starts = append(starts, time.Now())
+		go func() {
+			results <- hedgedResult{attemptResult: b.executeAdmitted(attemptCtx, baseURL, admitted), index: index}
+		}()
+		return nil
+	}
+	if failed := launch(); failed != nil {
+		return *failed
+	}
+
+	timer := time.NewTimer(config.delay())
+	defer timer.Stop()
+	var last attemptResult
+	for pending := 1; pending > 0; {
+		select {
+		case <-timer.C:
+			// Stop hedging once the limit is reached or no attempt can be admitted
+			if uint(len(cancels)) > config.MaxHedges || launch() != nil {
+				continue
+			}
+			pending++
+			timer.Reset(config.delay())
+		case result := <-results:
+			pending--
+			if result.err != nil {
+				last = result.attemptResult
+				continue
+			}
+			if config.Tracker != nil {
+				config.Tracker.Observe(time.Since(starts[result.index]))
+			}
+			// Cancel the losers, closing the responses that still arrive
+			for index, cancel := range cancels {
+				if index != result.index {
+					cancel(errHedgeLost)
+				}
+			}
+			go func() {
+				for range pending {
+					if loser := <-results; loser.response != nil {
+						_ = loser.response.Raw().Body.Close()
+					}
+				}
+			}()
+			result.response.onBodyClose(func() { cancels[result.index](nil) })
+			return result.attemptResult
+		}
+	}
+
+	for _, cancel := range cancels {
+		cancel(nil)
+	}
+	return last
+}
+
+func (b *RequestBuilder) executeWithRetry(ctx context.Context) (*Response, error) {
 	config := b.request.config.RetryConfig()
-	var errExecution error
+	start := time.Now()
+	criteria := b.baseURLCriteria()
+	var delay time.Duration
 	var errAttempts []error
-	var response *Response
+	var lastRequest *http.Request
+
+	// Notify give-up hooks once the request fails for good
+	giveUp := func(err error) (*Response, error) {
+		b.runGiveUpHooks(lastRequest, err)
+		return nil, err
+	}
 
 	for attempt := range config.MaxAttempts() {
-		// Execute request
-		response, errExecution = b.execute(req)
-		// Check for errors
+		// Execute a fresh request so the body is replayed on every attempt
+		result := b.executeHedgedAttempt(ctx, criteria)
+		if result.request != nil {
+			lastRequest = result.request
+		}
+		// Stop if the attempt could not be sent, e.g. when every circuit is open
+		if !result.sent {
+			if attempt == 0 {
+				return giveUp(result.err)
+			}
+			return giveUp(errors.Join(
+				fmt.Errorf("%w after %d attempts", result.err, attempt),
+				errors.Join(errAttempts...),
+			))
+		}
+		// Check whether the attempt should be retried
+		retry := b.shouldRetry(result.response, result.err, RetryAttempt{
+			Request:     result.request,
+			Number:      attempt + 1,
+			MaxAttempts: config.MaxAttempts(),
+			Elapsed:     time.Since(start),
+		})
+		errExecution := result.err
 		if errExecution == nil {
-			if !config.ShouldRetry()(response) {
-				return response, nil
+			if !retry {
+				return result.response, nil
 			}
-			errExecution = errors.New(response.Status().Text())
+			errExecution = errors.New(result.response.Status().Text())
+			// Release the connection of the discarded response
+			result.response.discard()
+		}
+		// Append error, recording the backend that failed
+		errAttempts = append(errAttempts, fmt.Errorf("attempt %d (%s): %w", attempt+1, result.baseURL, errExecution))
+		// Stop if the error is not worth retrying
+		if !retry {
+			return giveUp(
+				fmt.Errorf(
+					"request failed after %d attempts: %w",
+					attempt+1,
+					errors.Join(errAttempts...),
+				),
+			)
+		}
+		// Stop once the last attempt has been made
+		if attempt+1 == config.MaxAttempts() {
+			break
+		}
+		// Skip the failed backend on the next attempts, when enabled
+		if config.ExcludeFailedBaseURLs() {
+			criteria.Exclude = append(criteria.Exclude, result.baseURL)
 		}
-		// Append error
-		errAttempts = append(errAttempts, fmt.Errorf("attempt %d: %w", attempt+1, errExecution))
 		// Delay before retry
-		delay := b.calculateRetryDelay(attempt)
-		time.Sleep(delay)
+		delay = b.calculateRetryDelay(attempt, delay, result.response)
+		// Stop if the next attempt could not start within the retry deadline
+		if maxElapsed := config.MaxElapsedTime(); maxElapsed != nil && time.Since(start)+delay > *maxElapsed {
+			return giveUp(errors.Join(
+				fmt.Errorf("%s after %d attempts", constant.ErrMsgRetryDeadline, attempt+1),
+				errors.Join(errAttempts...),
+			))
+		}
+		// Stop if the client retry budget is spent
+		if budget := b.retryBudget(); budget != nil && !budget.TryRetry() {
+			return giveUp(errors.Join(
+				fmt.Errorf("%w after %d attempts", ErrRetryBudgetExhausted, attempt+1),
+				errors.Join(errAttempts...),
+			))
+		}
+		// Notify retry hooks before waiting
+		b.runRetryHooks(result.request, RetryEvent{
+			Attempt: attempt + 1,
+			Delay:   delay,
+			Cause:   errExecution,
+		})
+		if err := waitForRetry(ctx, delay); err != nil {
+			return giveUp(errors.Join(
+				fmt.Errorf("%s after %d attempts: %w", constant.ErrMsgRetryCanceled, attempt+1, err),
+				errors.Join(errAttempts...),
+			))
+		}
 	}
 
-	return nil,
+	return giveUp(
 		fmt.Errorf(
 			"request failed after %d attempts: %w",
 			config.MaxAttempts(),
 			errors.Join(errAttempts...),
-		)
+		),
+	)
+}
+
+func (b *RequestBuilder) shouldRetry(response *Response, err error, attempt RetryAttempt) bool {
+	config := b.request.config.RetryConfig()
+
+	// Error-aware predicate takes precedence
+	if predicate := config.RetryPredicate(); predicate != nil {
+		return predicate(response, err, attempt)
+	}
+
+	// Transport errors are always retried by the response-only condition
+	if err != nil {
+		return true
+	}
+	return config.ShouldRetry()(response)
 }
 
-func (b *RequestBuilder) calculateRetryDelay(attempt uint) time.Duration {
+func (b *RequestBuilder) calculateRetryDelay(attempt uint, previousDelay time.Duration, response *Response) time.Duration {
 	config := b.request.config.RetryConfig()
-	delay := float64(config.Interval()) * math.Pow(config.BackoffRate(), float64(attempt))
 
+	var maxDelay time.Duration
 	if config.MaxDelay() != nil {
-		delay = math.Min(delay, float64(*config.MaxDelay()))
+		maxDelay = *config.MaxDelay()
 	}
 
-	if config.JitterStrategy() == JitterStrategyFull {
-		delay = rand.Float64() * delay
+	// Prefer the delay requested by the server, when enabled, within the default cap unless a
+	// maximum delay is set
+	if config.RespectRetryAfter() && response != nil {
+		if delay, ok := retryAfterDelay(response.Raw().Header, time.Now()); ok {
+			return capDelay(delay, cmp.Or(maxDelay, defaultMaxRetryAfterDelay))
+		}
 	}
 
-	return time.Duration(delay)
+	delay := config.BackoffStrategy().NextDelay(BackoffState{
+		Attempt:       attempt,
+		PreviousDelay: previousDelay,
+		MaxDelay:      maxDelay,
+	})
+
+	return capDelay(delay, maxDelay)
+}
+
+// waitForRetry blocks for the given delay or until the context is done, whichever happens first.
+func waitForRetry(ctx context.Context, delay time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 func (b *RequestBuilder) Send() (*Response, error) {
@@ -190,17 +657,54 @@ func (b *RequestBuilder) Send() (*Response, error) {
 		return nil, errors.Join(errors.New(constant.ErrMsgRequestValidation), err)
 	}
 
-	// Create request
-	req, err := b.createHTTPRequest()
+	// Share the response of an identical request in flight, when enabled
+	ctx := b.request.config.Context().Unwrap()
+	if coalescer := b.coalescer(); coalescer != nil && coalescer.coalescable(b.request.config.Method()) {
+		key, err := b.coalescingKey(ctx, coalescer)
+		if err != nil {
+			return nil, errors.Join(errors.New(constant.ErrMsgCreateRequest), err)
+		}
+		return coalescer.do(ctx, key, b.send)
+	}
+
+	return b.send(ctx)
+}
+
+// coalescingKey returns the key that identifies the request for coalescing, derived from the
+// request as sent, with the client and request headers and cookies. Load-balanced clients leave
+// the base URL out, since it is picked per attempt among equivalent backends, and key on the
+// client and the hash key instead, so requests pinned to different backends and requests of
+// other clients sharing the coalescer are kept apart.
+func (b *RequestBuilder) coalescingKey(ctx context.Context, coalescer *Coalescer) (string, error) {
+	fullURL, scope := b.createFullURL().String(), ""
+	if balancer, ok := b.request.client.(BaseURLBalancer); ok && balancer.Balanced() {
+		fullURL = b.createFullURLFor(&url.URL{}).String()
+		scope = fmt.Sprintf("%p %q", b.request.client, b.baseURLCriteria().HashKey)
+	}
+
+	req, err := b.newHTTPRequest(ctx, fullURL, nil)
 	if err != nil {
-		return nil, errors.Join(errors.New(constant.ErrMsgCreateRequest), err)
+		return "", err
+	}
+	return coalescer.key(req, scope), nil
+}
+
+// send sends the request within the given context, retrying it when enabled.
+func (b *RequestBuilder) send(ctx context.Context) (*Response, error) {
+	// Deposit the request into the client retry budget
+	if budget := b.retryBudget(); budget != nil {
+		budget.RecordRequest()
 	}
 
 	// Check if maxAttempts are enabled
 	if b.request.config.RetryConfig() != nil && b.request.config.RetryConfig().MaxAttempts() > 1 {
-		return b.executeWithRetry(req)
+		return b.executeWithRetry(ctx)
 	}
 
 	// Execute the request
-	return b.execute(req)
+	result := b.executeHedgedAttempt(ctx, b.baseURLCriteria())
+	if result.err != nil {
+		b.runGiveUpHooks(result.request, result.err)
+	}
+	return result.response, result.err
 }
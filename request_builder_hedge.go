@@ -0,0 +1,47 @@
+package fastshot
+
+import (
+	"errors"
+	"time"
+
+	"github.com/opus-domini/fast-shot/constant"
+)
+
+// BuilderRequestHedge is the interface that wraps the basic methods for hedging a request.
+var _ BuilderRequestHedge[RequestBuilder] = (*RequestHedgeBuilder)(nil)
+
+// RequestHedgeBuilder serves as the main entry point for hedging a request.
+type RequestHedgeBuilder struct {
+	parentBuilder *RequestBuilder
+	requestConfig *RequestConfigBase
+}
+
+// Hedge returns a new RequestHedgeBuilder for sending duplicate attempts of slow requests.
+func (b *RequestBuilder) Hedge() *RequestHedgeBuilder {
+	return &RequestHedgeBuilder{
+		parentBuilder: b,
+		requestConfig: b.request.config,
+	}
+}
+
+// SetFixedDelay sends up to maxHedges duplicate attempts, one each time no response arrives within the delay.
+func (b *RequestHedgeBuilder) SetFixedDelay(delay time.Duration, maxHedges uint) *RequestBuilder {
+	return b.set(&HedgeConfig{Delay: delay, MaxHedges: maxHedges})
+}
+
+// SetPercentileDelay sends up to maxHedges duplicate attempts, one each time no response arrives
+// within the given percentile of the latencies recorded by the tracker. The latency of the winning
+// attempt is recorded by the tracker, which can be shared by the requests to the same endpoint.
+func (b *RequestHedgeBuilder) SetPercentileDelay(tracker *LatencyTracker, percentile float64, maxHedges uint) *RequestBuilder {
+	return b.set(&HedgeConfig{Tracker: tracker, Percentile: percentile, MaxHedges: maxHedges})
+}
+
+// set sets the hedging configuration, recording a validation error for non-idempotent methods.
+func (b *RequestHedgeBuilder) set(config *HedgeConfig) *RequestBuilder {
+	if !isIdempotentMethod(b.requestConfig.Method()) {
+		b.requestConfig.Validations().Add(errors.New(constant.ErrMsgHedgeNonIdempotent))
+		return b.parentBuilder
+	}
+	b.requestConfig.SetHedgeConfig(config)
+	return b.parentBuilder
+}
@@ -0,0 +1,77 @@
+package fastshot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opus-domini/fast-shot/constant"
+	"github.com/opus-domini/fast-shot/constant/method"
+)
+
+func TestRequestHedgeBuilder(t *testing.T) {
+	tracker := NewLatencyTracker(10, time.Millisecond)
+	tests := []struct {
+		name          string
+		method        method.Type
+		setup         func(*RequestHedgeBuilder) *RequestBuilder
+		expected      *HedgeConfig
+		expectedError string
+	}{
+		{
+			name:   "Set fixed delay",
+			method: method.GET,
+			setup: func(b *RequestHedgeBuilder) *RequestBuilder {
+				return b.SetFixedDelay(50*time.Millisecond, 2)
+			},
+			expected: &HedgeConfig{Delay: 50 * time.Millisecond, MaxHedges: 2},
+		},
+		{
+			name:   "Set percentile delay",
+			method: method.PUT,
+			setup: func(b *RequestHedgeBuilder) *RequestBuilder {
+				return b.SetPercentileDelay(tracker, 95, 1)
+			},
+			expected: &HedgeConfig{Tracker: tracker, Percentile: 95, MaxHedges: 1},
+		},
+		{
+			name:   "Non-idempotent method",
+			method: method.POST,
+			setup: func(b *RequestHedgeBuilder) *RequestBuilder {
+				return b.SetFixedDelay(50*time.Millisecond, 2)
+			},
+			expectedError: constant.ErrMsgHedgeNonIdempotent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			rb := &RequestBuilder{
+				request: &Request{
+					config: newRequestConfigBase(tt.method, ""),
+				},
+			}
+
+			// Act
+			result := tt.setup(rb.Hedge())
+
+			// Assert
+			if result != rb {
+				t.Error("Hedge() did not return the parent builder")
+			}
+			config := rb.request.config.HedgeConfig()
+			if tt.expectedError != "" {
+				if config != nil {
+					t.Errorf("hedge config got %+v, want nil", config)
+				}
+				if err := rb.request.config.Validations().Get(0); err == nil || err.Error() != tt.expectedError {
+					t.Errorf("validation got %v, want %q", err, tt.expectedError)
+				}
+				return
+			}
+			if config == nil || *config != *tt.expected {
+				t.Errorf("got %+v, want %+v", config, tt.expected)
+			}
+		})
+	}
+}
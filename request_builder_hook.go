@@ -7,7 +7,7 @@ import (
 // BuilderHook is the interface that wraps the basic methods for setting request hooks.
 var _ BuilderHook[RequestBuilder] = (*RequestHookBuilder)(nil)
 
-// RequestHookBuilder allows for setting pre-request and post-response hooks at the request level.
+// RequestHookBuilder allows for setting request lifecycle hooks at the request level.
 type RequestHookBuilder struct {
 	parentBuilder *RequestBuilder
 	requestConfig *RequestConfigBase
@@ -32,3 +32,21 @@ func (b *RequestHookBuilder) OnAfterResponse(hook func(*http.Request, *http.Resp
 	b.requestConfig.AddAfterResponseHook(hook)
 	return b.parentBuilder
 }
+
+// OnRetry adds a retry-scheduled hook to the request.
+func (b *RequestHookBuilder) OnRetry(hook func(*http.Request, RetryEvent)) *RequestBuilder {
+	b.requestConfig.AddRetryHook(hook)
+	return b.parentBuilder
+}
+
+// OnError adds a transport-error hook to the request.
+func (b *RequestHookBuilder) OnError(hook func(*http.Request, error)) *RequestBuilder {
+	b.requestConfig.AddErrorHook(hook)
+	return b.parentBuilder
+}
+
+// OnGiveUp adds a give-up hook to the request.
+func (b *RequestHookBuilder) OnGiveUp(hook func(*http.Request, error)) *RequestBuilder {
+	b.requestConfig.AddGiveUpHook(hook)
+	return b.parentBuilder
+}
@@ -55,6 +55,26 @@ func TestRequestHookBuilder(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Add retry, error and give-up hooks",
+			setup: func(rb *RequestBuilder) *RequestBuilder {
+				return rb.
+					Hook().OnRetry(func(req *http.Request, event RetryEvent) {}).
+					Hook().OnError(func(req *http.Request, err error) {}).
+					Hook().OnGiveUp(func(req *http.Request, err error) {})
+			},
+			assertFunc: func(t *testing.T, rb *RequestBuilder) {
+				if got := len(rb.request.config.RetryHooks()); got != 1 {
+					t.Errorf("retry hooks count got %d, want 1", got)
+				}
+				if got := len(rb.request.config.ErrorHooks()); got != 1 {
+					t.Errorf("error hooks count got %d, want 1", got)
+				}
+				if got := len(rb.request.config.GiveUpHooks()); got != 1 {
+					t.Errorf("give-up hooks count got %d, want 1", got)
+				}
+			},
+		},
 		{
 			name: "No hooks by default",
 			setup: func(rb *RequestBuilder) *RequestBuilder {
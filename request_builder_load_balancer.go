@@ -0,0 +1,25 @@
+package fastshot
+
+// BuilderRequestLoadBalancer is the interface that wraps the basic methods for steering a request on load-balanced clients.
+var _ BuilderRequestLoadBalancer[RequestBuilder] = (*RequestLoadBalancerBuilder)(nil)
+
+// RequestLoadBalancerBuilder serves as the main entry point for steering a request on load-balanced clients.
+type RequestLoadBalancerBuilder struct {
+	parentBuilder *RequestBuilder
+	requestConfig *RequestConfigBase
+}
+
+// LoadBalancer returns a new RequestLoadBalancerBuilder for steering the request across base URLs.
+func (b *RequestBuilder) LoadBalancer() *RequestLoadBalancerBuilder {
+	return &RequestLoadBalancerBuilder{
+		parentBuilder: b,
+		requestConfig: b.request.config,
+	}
+}
+
+// WithHashKey sets the key consistent hashing strategies use to pick the base URL of the request.
+// It takes precedence over the key the client derives from the request.
+func (b *RequestLoadBalancerBuilder) WithHashKey(key string) *RequestBuilder {
+	b.requestConfig.SetHashKey(key)
+	return b.parentBuilder
+}
@@ -0,0 +1,44 @@
+package fastshot
+
+import "testing"
+
+func TestRequestLoadBalancerBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		expected string
+	}{
+		{
+			name:     "Set hash key",
+			key:      "tenant-42",
+			expected: "tenant-42",
+		},
+		{
+			name:     "Set empty hash key",
+			key:      "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			rb := &RequestBuilder{
+				request: &Request{
+					config: newRequestConfigBase("", ""),
+				},
+			}
+
+			// Act
+			result := rb.LoadBalancer().WithHashKey(tt.key)
+
+			// Assert
+			if result != rb {
+				t.Error("WithHashKey() did not return the parent builder")
+			}
+			if got := rb.request.config.HashKey(); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
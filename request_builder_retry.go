@@ -21,37 +21,32 @@ func (b *RequestBuilder) Retry() *RequestRetryBuilder {
 
 // SetConstantBackoff sets the retry interval and maximum attempts.
 func (r RequestRetryBuilder) SetConstantBackoff(interval time.Duration, maxAttempts uint) *RequestBuilder {
-	r.requestConfig.RetryConfig().SetInterval(interval)
-	r.requestConfig.RetryConfig().SetMaxAttempts(maxAttempts)
-	r.requestConfig.RetryConfig().SetBackoffRate(1)
-	r.requestConfig.RetryConfig().SetJitterStrategy(JitterStrategyNone)
+	r.requestConfig.RetryConfig().setBackoffPreset(interval, maxAttempts, 1, JitterStrategyNone)
 	return r.parentBuilder
 }
 
 // SetConstantBackoffWithJitter sets the retry interval and maximum attempts.
 func (r RequestRetryBuilder) SetConstantBackoffWithJitter(interval time.Duration, maxAttempts uint) *RequestBuilder {
-	r.requestConfig.RetryConfig().SetInterval(interval)
-	r.requestConfig.RetryConfig().SetMaxAttempts(maxAttempts)
-	r.requestConfig.RetryConfig().SetBackoffRate(1)
-	r.requestConfig.RetryConfig().SetJitterStrategy(JitterStrategyFull)
+	r.requestConfig.RetryConfig().setBackoffPreset(interval, maxAttempts, 1, JitterStrategyFull)
 	return r.parentBuilder
 }
 
 // SetExponentialBackoff sets the retry interval, maximum attempts, and backoff rate.
 func (r RequestRetryBuilder) SetExponentialBackoff(interval time.Duration, maxAttempts uint, backoffRate float64) *RequestBuilder {
-	r.requestConfig.RetryConfig().SetInterval(interval)
-	r.requestConfig.RetryConfig().SetMaxAttempts(maxAttempts)
-	r.requestConfig.RetryConfig().SetBackoffRate(backoffRate)
-	r.requestConfig.RetryConfig().SetJitterStrategy(JitterStrategyNone)
+	r.requestConfig.RetryConfig().setBackoffPreset(interval, maxAttempts, backoffRate, JitterStrategyNone)
 	return r.parentBuilder
 }
 
 // SetExponentialBackoffWithJitter sets the retry interval, maximum attempts, and backoff rate.
 func (r RequestRetryBuilder) SetExponentialBackoffWithJitter(interval time.Duration, maxAttempts uint, backoffRate float64) *RequestBuilder {
-	r.requestConfig.RetryConfig().SetInterval(interval)
+	r.requestConfig.RetryConfig().setBackoffPreset(interval, maxAttempts, backoffRate, JitterStrategyFull)
+	return r.parentBuilder
+}
+
+// SetBackoffStrategy sets a custom backoff strategy and maximum attempts.
+func (r RequestRetryBuilder) SetBackoffStrategy(strategy BackoffStrategy, maxAttempts uint) *RequestBuilder {
 	r.requestConfig.RetryConfig().SetMaxAttempts(maxAttempts)
-	r.requestConfig.RetryConfig().SetBackoffRate(backoffRate)
-	r.requestConfig.RetryConfig().SetJitterStrategy(JitterStrategyFull)
+	r.requestConfig.RetryConfig().SetBackoffStrategy(strategy)
 	return r.parentBuilder
 }
 
@@ -61,8 +56,51 @@ func (r RequestRetryBuilder) WithRetryCondition(shouldRetry func(response *Respo
 	return r.parentBuilder
 }
 
+// WithRetryPredicate sets an error-aware retry condition for the request. Unlike WithRetryCondition,
+// the predicate also decides whether transport errors are retried, and takes precedence when both are set.
+func (r RequestRetryBuilder) WithRetryPredicate(predicate RetryPredicate) *RequestBuilder {
+	r.requestConfig.RetryConfig().SetRetryPredicate(predicate)
+	return r.parentBuilder
+}
+
 // WithMaxDelay sets the maximum delay for the request.
 func (r RequestRetryBuilder) WithMaxDelay(duration time.Duration) *RequestBuilder {
 	r.requestConfig.RetryConfig().SetMaxDelay(duration)
 	return r.parentBuilder
 }
+
+// WithMaxElapsedTime sets the overall retry deadline, measured from the first attempt.
+// No further attempt is made once the next one could not start before the deadline.
+func (r RequestRetryBuilder) WithMaxElapsedTime(duration time.Duration) *RequestBuilder {
+	r.requestConfig.RetryConfig().SetMaxElapsedTime(duration)
+	return r.parentBuilder
+}
+
+// WithPerAttemptTimeout gives each attempt its own deadline, within the request context.
+// A hung attempt then fails on its own and the next attempt can still succeed.
+// The timeout also bounds reading the body of the returned response.
+func (r RequestRetryBuilder) WithPerAttemptTimeout(duration time.Duration) *RequestBuilder {
+	r.requestConfig.RetryConfig().SetPerAttemptTimeout(duration)
+	return r.parentBuilder
+}
+
+// WithRetryAfter makes the retry delay follow the Retry-After, RateLimit-Reset or X-RateLimit-Reset
+// header of the last response, when present. The delay is still capped by WithMaxDelay, or at
+// five minutes when no maximum delay is set.
+func (r RequestRetryBuilder) WithRetryAfter() *RequestBuilder {
+	r.requestConfig.RetryConfig().SetRespectRetryAfter(true)
+	return r.parentBuilder
+}
+
+// WithFailover makes retries of load-balanced clients skip the base URLs that already failed
+// for the same request, as long as other base URLs remain.
+func (r RequestRetryBuilder) WithFailover() *RequestBuilder {
+	r.requestConfig.RetryConfig().SetExcludeFailedBaseURLs(true)
+	return r.parentBuilder
+}
+
+// Disable turns off retries for the request, including any policy inherited from the client.
+func (r RequestRetryBuilder) Disable() *RequestBuilder {
+	r.requestConfig.RetryConfig().SetMaxAttempts(0)
+	return r.parentBuilder
+}
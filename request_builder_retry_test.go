@@ -53,10 +53,22 @@ func TestRequestRetryBuilder(t *testing.T) {
 				return rb.Retry().SetExponentialBackoffWithJitter(time.Second, 3, 2.0)
 			},
 			expectedConfig: func(rc *RetryConfig) bool {
+				_, ok := rc.BackoffStrategy().(*FullJitterBackoff)
 				return rc.Interval() == time.Second &&
 					rc.MaxAttempts() == 3 &&
 					rc.BackoffRate() == 2.0 &&
-					rc.JitterStrategy() == JitterStrategyFull
+					rc.JitterStrategy() == JitterStrategyFull &&
+					ok
+			},
+		},
+		{
+			name: "Set backoff strategy",
+			method: func(rb *RequestBuilder) *RequestBuilder {
+				return rb.Retry().SetBackoffStrategy(NewFibonacciBackoff(time.Second), 4)
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				_, ok := rc.BackoffStrategy().(*FibonacciBackoff)
+				return ok && rc.MaxAttempts() == 4
 			},
 		},
 		{
@@ -70,6 +82,15 @@ func TestRequestRetryBuilder(t *testing.T) {
 				return rc.ShouldRetry() != nil
 			},
 		},
+		{
+			name: "Set retry predicate",
+			method: func(rb *RequestBuilder) *RequestBuilder {
+				return rb.Retry().WithRetryPredicate(RetryOn5xx)
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.RetryPredicate() != nil
+			},
+		},
 		{
 			name: "Set max delay",
 			method: func(rb *RequestBuilder) *RequestBuilder {
@@ -79,6 +100,51 @@ func TestRequestRetryBuilder(t *testing.T) {
 				return rc.MaxDelay() != nil && *rc.MaxDelay() == 5*time.Second
 			},
 		},
+		{
+			name: "Set max elapsed time",
+			method: func(rb *RequestBuilder) *RequestBuilder {
+				return rb.Retry().WithMaxElapsedTime(10 * time.Second)
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.MaxElapsedTime() != nil && *rc.MaxElapsedTime() == 10*time.Second
+			},
+		},
+		{
+			name: "Set per-attempt timeout",
+			method: func(rb *RequestBuilder) *RequestBuilder {
+				return rb.Retry().WithPerAttemptTimeout(2 * time.Second)
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.PerAttemptTimeout() != nil && *rc.PerAttemptTimeout() == 2*time.Second
+			},
+		},
+		{
+			name: "Set retry after",
+			method: func(rb *RequestBuilder) *RequestBuilder {
+				return rb.Retry().WithRetryAfter()
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.RespectRetryAfter()
+			},
+		},
+		{
+			name: "Set failover",
+			method: func(rb *RequestBuilder) *RequestBuilder {
+				return rb.Retry().WithFailover()
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.ExcludeFailedBaseURLs()
+			},
+		},
+		{
+			name: "Disable",
+			method: func(rb *RequestBuilder) *RequestBuilder {
+				return rb.Retry().SetConstantBackoff(time.Second, 3).Retry().Disable()
+			},
+			expectedConfig: func(rc *RetryConfig) bool {
+				return rc.MaxAttempts() == 0
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -20,21 +20,42 @@ type (
 		body          BodyWrapper
 		validations   ValidationsWrapper
 		retryConfig   *RetryConfig
+		hashKey       string
+		hedgeConfig   *HedgeConfig
 		beforeRequest []func(*http.Request) error
 		afterResponse []func(*http.Request, *http.Response)
+		onRetry       []func(*http.Request, RetryEvent)
+		onError       []func(*http.Request, error)
+		onGiveUp      []func(*http.Request, error)
 	}
 
 	// JitterStrategy represents the strategy for jitter.
 	JitterStrategy string
 
+	// RetryEvent describes a retry that has been scheduled after a failed attempt.
+	RetryEvent struct {
+		// Attempt is the 1-based number of the attempt that failed.
+		Attempt uint
+		// Delay is the time waited before the next attempt.
+		Delay time.Duration
+		// Cause is the transport error or the status of the failed attempt.
+		Cause error
+	}
+
 	// RetryConfig represents the configuration for the retry mechanism.
 	RetryConfig struct {
-		shouldRetry    func(response *Response) bool
-		interval       time.Duration
-		maxAttempts    uint
-		backoffRate    float64
-		maxDelay       *time.Duration
-		jitterStrategy JitterStrategy
+		shouldRetry       func(response *Response) bool
+		retryPredicate    RetryPredicate
+		interval          time.Duration
+		maxAttempts       uint
+		backoffRate       float64
+		maxDelay          *time.Duration
+		maxElapsedTime    *time.Duration
+		perAttemptTimeout *time.Duration
+		jitterStrategy    JitterStrategy
+		backoffStrategy   BackoffStrategy
+		respectRetryAfter bool
+		excludeFailed     bool
 	}
 )
 
@@ -90,6 +111,26 @@ func (c *RequestConfigBase) RetryConfig() *RetryConfig {
 	return c.retryConfig
 }
 
+// HashKey returns the key consistent hashing load balancers use to pick the base URL, if any.
+func (c *RequestConfigBase) HashKey() string {
+	return c.hashKey
+}
+
+// SetHashKey sets the key consistent hashing load balancers use to pick the base URL.
+func (c *RequestConfigBase) SetHashKey(key string) {
+	c.hashKey = key
+}
+
+// HedgeConfig returns the hedging configuration for the request, if any.
+func (c *RequestConfigBase) HedgeConfig() *HedgeConfig {
+	return c.hedgeConfig
+}
+
+// SetHedgeConfig sets the hedging configuration for the request.
+func (c *RequestConfigBase) SetHedgeConfig(config *HedgeConfig) {
+	c.hedgeConfig = config
+}
+
 // BeforeRequestHooks returns the before-request hooks for the request.
 func (c *RequestConfigBase) BeforeRequestHooks() []func(*http.Request) error {
 	return c.beforeRequest
@@ -111,6 +152,36 @@ func (c *RequestConfigBase) AddAfterResponseHook(hook func(*http.Request, *http.
 	c.afterResponse = append(c.afterResponse, hook)
 }
 
+// RetryHooks returns the retry-scheduled hooks for the request.
+func (c *RequestConfigBase) RetryHooks() []func(*http.Request, RetryEvent) {
+	return c.onRetry
+}
+
+// ErrorHooks returns the transport-error hooks for the request.
+func (c *RequestConfigBase) ErrorHooks() []func(*http.Request, error) {
+	return c.onError
+}
+
+// GiveUpHooks returns the give-up hooks for the request.
+func (c *RequestConfigBase) GiveUpHooks() []func(*http.Request, error) {
+	return c.onGiveUp
+}
+
+// AddRetryHook appends a retry-scheduled hook to the request.
+func (c *RequestConfigBase) AddRetryHook(hook func(*http.Request, RetryEvent)) {
+	c.onRetry = append(c.onRetry, hook)
+}
+
+// AddErrorHook appends a transport-error hook to the request.
+func (c *RequestConfigBase) AddErrorHook(hook func(*http.Request, error)) {
+	c.onError = append(c.onError, hook)
+}
+
+// AddGiveUpHook appends a give-up hook to the request.
+func (c *RequestConfigBase) AddGiveUpHook(hook func(*http.Request, error)) {
+	c.onGiveUp = append(c.onGiveUp, hook)
+}
+
 // ShouldRetry returns the retry condition for the request.
 func (c *RetryConfig) ShouldRetry() func(response *Response) bool {
 	return c.shouldRetry
@@ -121,12 +192,22 @@ func (c *RetryConfig) SetShouldRetry(shouldRetry func(response *Response) bool)
 	c.shouldRetry = shouldRetry
 }
 
+// RetryPredicate returns the error-aware retry predicate for the request.
+func (c *RetryConfig) RetryPredicate() RetryPredicate {
+	return c.retryPredicate
+}
+
+// SetRetryPredicate sets the error-aware retry predicate for the request.
+func (c *RetryConfig) SetRetryPredicate(predicate RetryPredicate) {
+	c.retryPredicate = predicate
+}
+
 // Interval returns the retry interval for the request.
 func (c *RetryConfig) Interval() time.Duration {
 	return c.interval
 }
 
-// SetInterval sets the retry interval for the request.
+// SetInterval sets the retry interval for the request. It is ignored while a BackoffStrategy is set.
 func (c *RetryConfig) SetInterval(duration time.Duration) {
 	c.interval = duration
 }
@@ -146,7 +227,7 @@ func (c *RetryConfig) BackoffRate() float64 {
 	return c.backoffRate
 }
 
-// SetBackoffRate sets the retry backoff rate for the request.
+// SetBackoffRate sets the retry backoff rate for the request. It is ignored while a BackoffStrategy is set.
 func (c *RetryConfig) SetBackoffRate(rate float64) {
 	c.backoffRate = rate
 }
@@ -161,16 +242,116 @@ func (c *RetryConfig) SetMaxDelay(duration time.Duration) {
 	c.maxDelay = &duration
 }
 
+// MaxElapsedTime returns the overall retry deadline for the request.
+func (c *RetryConfig) MaxElapsedTime() *time.Duration {
+	return c.maxElapsedTime
+}
+
+// SetMaxElapsedTime sets the overall retry deadline for the request.
+func (c *RetryConfig) SetMaxElapsedTime(duration time.Duration) {
+	c.maxElapsedTime = &duration
+}
+
+// PerAttemptTimeout returns the timeout applied to each attempt of the request.
+func (c *RetryConfig) PerAttemptTimeout() *time.Duration {
+	return c.perAttemptTimeout
+}
+
+// SetPerAttemptTimeout sets the timeout applied to each attempt of the request.
+func (c *RetryConfig) SetPerAttemptTimeout(duration time.Duration) {
+	c.perAttemptTimeout = &duration
+}
+
 // JitterStrategy returns the retry jitter strategy for the request.
 func (c *RetryConfig) JitterStrategy() JitterStrategy {
 	return c.jitterStrategy
 }
 
-// SetJitterStrategy sets the retry jitter strategy for the request.
+// SetJitterStrategy sets the retry jitter strategy for the request. It is ignored while a
+// BackoffStrategy is set.
 func (c *RetryConfig) SetJitterStrategy(strategy JitterStrategy) {
 	c.jitterStrategy = strategy
 }
 
+// BackoffStrategy returns the retry backoff strategy for the request. When no strategy is set,
+// it is derived from the interval, backoff rate and jitter strategy.
+func (c *RetryConfig) BackoffStrategy() BackoffStrategy {
+	if c.backoffStrategy == nil {
+		return newLegacyBackoff(c.interval, c.backoffRate, c.jitterStrategy)
+	}
+	return c.backoffStrategy
+}
+
+// SetBackoffStrategy sets the retry backoff strategy for the request. It takes precedence over the
+// interval, backoff rate and jitter strategy; setting nil derives the strategy from them again.
+func (c *RetryConfig) SetBackoffStrategy(strategy BackoffStrategy) {
+	c.backoffStrategy = strategy
+}
+
+// RespectRetryAfter returns whether server-provided retry delays are honored for the request.
+func (c *RetryConfig) RespectRetryAfter() bool {
+	return c.respectRetryAfter
+}
+
+// SetRespectRetryAfter sets whether server-provided retry delays are honored for the request.
+func (c *RetryConfig) SetRespectRetryAfter(respect bool) {
+	c.respectRetryAfter = respect
+}
+
+// ExcludeFailedBaseURLs returns whether retries skip base URLs that already failed for the request.
+func (c *RetryConfig) ExcludeFailedBaseURLs() bool {
+	return c.excludeFailed
+}
+
+// SetExcludeFailedBaseURLs sets whether retries skip base URLs that already failed for the request.
+func (c *RetryConfig) SetExcludeFailedBaseURLs(exclude bool) {
+	c.excludeFailed = exclude
+}
+
+// setBackoffPreset sets the maximum attempts and a built-in backoff: constant when the backoff
+// rate is 1 and exponential otherwise, with full jitter when asked. The interval, backoff rate and
+// jitter strategy are kept in line with it.
+func (c *RetryConfig) setBackoffPreset(interval time.Duration, maxAttempts uint, backoffRate float64, jitter JitterStrategy) {
+	var strategy BackoffStrategy = NewExponentialBackoff(interval, backoffRate)
+	if backoffRate == 1 {
+		strategy = NewConstantBackoff(interval)
+	}
+	if jitter == JitterStrategyFull {
+		strategy = NewFullJitterBackoff(strategy)
+	}
+
+	c.SetInterval(interval)
+	c.SetMaxAttempts(maxAttempts)
+	c.SetBackoffRate(backoffRate)
+	c.SetJitterStrategy(jitter)
+	c.SetBackoffStrategy(strategy)
+}
+
+// clone returns a copy of the retry configuration that can be modified independently.
+func (c *RetryConfig) clone() *RetryConfig {
+	clone := *c
+	if c.maxDelay != nil {
+		clone.SetMaxDelay(*c.maxDelay)
+	}
+	if c.maxElapsedTime != nil {
+		clone.SetMaxElapsedTime(*c.maxElapsedTime)
+	}
+	if c.perAttemptTimeout != nil {
+		clone.SetPerAttemptTimeout(*c.perAttemptTimeout)
+	}
+	return &clone
+}
+
+// newDefaultRetryConfig creates a retry configuration with retries disabled.
+func newDefaultRetryConfig() *RetryConfig {
+	return &RetryConfig{
+		shouldRetry:    func(response *Response) bool { return response.Status().IsError() },
+		interval:       1 * time.Second,
+		backoffRate:    2.0,
+		jitterStrategy: JitterStrategyNone,
+	}
+}
+
 // NewRequestConfigBase creates a new request configuration.
 func newRequestConfigBase(method method.Type, path string) *RequestConfigBase {
 	return &RequestConfigBase{
@@ -182,11 +363,6 @@ func newRequestConfigBase(method method.Type, path string) *RequestConfigBase {
 		queryParams: url.Values{},
 		body:        newBufferedBody(),
 		validations: newDefaultValidations(nil),
-		retryConfig: &RetryConfig{
-			shouldRetry:    func(response *Response) bool { return response.Status().IsError() },
-			interval:       1 * time.Second,
-			backoffRate:    2.0,
-			jitterStrategy: JitterStrategyNone,
-		},
+		retryConfig: newDefaultRetryConfig(),
 	}
 }
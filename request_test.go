@@ -2,15 +2,19 @@ package fastshot
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
@@ -586,6 +590,23 @@ func TestRequest_Retry(t *testing.T) {
 			expectedStatus:   http.StatusOK,
 			expectedBody:     "Success",
 		},
+		{
+			name:        "Success with decorrelated jitter backoff strategy",
+			setupClient: DefaultClient,
+			request: func(client ClientHttpMethods) *RequestBuilder {
+				return client.GET("/test").
+					Retry().SetBackoffStrategy(NewDecorrelatedJitterBackoff(5*time.Millisecond), 4).
+					Retry().WithMaxDelay(20 * time.Millisecond)
+			},
+			serverResponses: []int{
+				http.StatusInternalServerError,
+				http.StatusInternalServerError,
+				http.StatusOK,
+			},
+			expectedAttempts: 3,
+			expectedStatus:   http.StatusOK,
+			expectedBody:     "Success",
+		},
 		{
 			name:        "Success on first attempt with no retry",
 			setupClient: DefaultClient,
@@ -1022,3 +1043,1605 @@ func TestRequest_Hooks(t *testing.T) {
 		}
 	})
 }
+
+func TestRequest_RetryReplaysBody(t *testing.T) {
+	// Arrange
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	var hookCount atomic.Int32
+	client := NewClient(server.URL).
+		Hook().OnBeforeRequest(func(req *http.Request) error {
+		hookCount.Add(1)
+		return nil
+	}).
+		Build()
+
+	// Act
+	resp, err := client.POST("/test").
+		Body().AsString(`{"name":"payload"}`).
+		Retry().SetConstantBackoff(time.Millisecond, 3).
+		Send()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resp.Status().Code(); got != http.StatusCreated {
+		t.Errorf("status got %d, want %d", got, http.StatusCreated)
+	}
+	if len(bodies) != 3 {
+		t.Fatalf("attempts got %d, want 3", len(bodies))
+	}
+	for i, body := range bodies {
+		if body != `{"name":"payload"}` {
+			t.Errorf("attempt %d body got %q, want %q", i+1, body, `{"name":"payload"}`)
+		}
+	}
+	if got := hookCount.Load(); got != 3 {
+		t.Errorf("hook count got %d, want 3", got)
+	}
+}
+
+type closeTrackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *closeTrackingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestRequest_RetryClosesDiscardedResponses(t *testing.T) {
+	// Arrange
+	var bodies []*closeTrackingBody
+	var requests []*http.Request
+	mockClient := &mock.HttpClientComponent{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req)
+			body := &closeTrackingBody{Reader: strings.NewReader("unavailable")}
+			bodies = append(bodies, body)
+			status := http.StatusServiceUnavailable
+			if len(bodies) == 3 {
+				status = http.StatusOK
+			}
+			return &http.Response{StatusCode: status, Body: body, Header: http.Header{}, Request: req}, nil
+		},
+	}
+	client := NewClient("https://example.com").
+		Config().SetCustomHttpClient(mockClient).
+		Build()
+
+	// Act
+	resp, err := client.GET("/test").
+		Retry().SetConstantBackoff(time.Millisecond, 3).
+		Send()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resp.Status().Code(); got != http.StatusOK {
+		t.Errorf("status got %d, want %d", got, http.StatusOK)
+	}
+	if len(bodies) != 3 {
+		t.Fatalf("attempts got %d, want 3", len(bodies))
+	}
+	if !bodies[0].closed || !bodies[1].closed {
+		t.Error("discarded response bodies were not closed")
+	}
+	if bodies[2].closed {
+		t.Error("returned response body was closed, want open")
+	}
+	if requests[0] == requests[1] || requests[1] == requests[2] {
+		t.Error("attempts shared the same *http.Request, want a fresh request per attempt")
+	}
+}
+
+func TestRequest_RetryContext(t *testing.T) {
+	t.Run("Cancellation interrupts backoff", func(t *testing.T) {
+		// Arrange
+		var attemptCount atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attemptCount.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+		}()
+
+		// Act
+		start := time.Now()
+		resp, err := DefaultClient(server.URL).GET("/test").
+			Context().Set(ctx).
+			Retry().SetConstantBackoff(10*time.Second, 3).
+			Send()
+		elapsed := time.Since(start)
+
+		// Assert
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("error %q does not wrap context.Canceled", err)
+		}
+		if !strings.Contains(err.Error(), constant.ErrMsgRetryCanceled) {
+			t.Errorf("error %q does not contain %q", err.Error(), constant.ErrMsgRetryCanceled)
+		}
+		if resp != nil {
+			t.Errorf("resp got %v, want nil", resp)
+		}
+		if elapsed > 5*time.Second {
+			t.Errorf("elapsed got %v, want the backoff to be interrupted", elapsed)
+		}
+		if got := attemptCount.Load(); got != 1 {
+			t.Errorf("attempts got %d, want 1", got)
+		}
+	})
+
+	t.Run("Max elapsed time stops retrying", func(t *testing.T) {
+		// Arrange
+		var attemptCount atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attemptCount.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		// Act
+		resp, err := DefaultClient(server.URL).GET("/test").
+			Retry().SetConstantBackoff(40*time.Millisecond, 10).
+			Retry().WithMaxElapsedTime(100 * time.Millisecond).
+			Send()
+
+		// Assert
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), constant.ErrMsgRetryDeadline) {
+			t.Errorf("error %q does not contain %q", err.Error(), constant.ErrMsgRetryDeadline)
+		}
+		if resp != nil {
+			t.Errorf("resp got %v, want nil", resp)
+		}
+		if got := attemptCount.Load(); got < 2 || got > 3 {
+			t.Errorf("attempts got %d, want 2 or 3", got)
+		}
+	})
+}
+
+func TestRequest_RetryAfter(t *testing.T) {
+	tests := []struct {
+		name          string
+		retryAfter    string
+		configure     func(*RequestBuilder) *RequestBuilder
+		expectedDelay func(time.Duration) bool
+	}{
+		{
+			name:       "Server delay replaces backoff",
+			retryAfter: "0",
+			configure: func(rb *RequestBuilder) *RequestBuilder {
+				return rb.Retry().SetConstantBackoff(10*time.Second, 2).
+					Retry().WithRetryAfter()
+			},
+			expectedDelay: func(d time.Duration) bool { return d < 5*time.Second },
+		},
+		{
+			name:       "Server delay is capped by max delay",
+			retryAfter: "60",
+			configure: func(rb *RequestBuilder) *RequestBuilder {
+				return rb.Retry().SetConstantBackoff(time.Millisecond, 2).
+					Retry().WithMaxDelay(20 * time.Millisecond).
+					Retry().WithRetryAfter()
+			},
+			expectedDelay: func(d time.Duration) bool { return d >= 20*time.Millisecond && d < 5*time.Second },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var attemptCount atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if attemptCount.Add(1) == 1 {
+					w.Header().Set(header.RetryAfter.String(), tt.retryAfter)
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			// Act
+			start := time.Now()
+			resp, err := tt.configure(DefaultClient(server.URL).GET("/test")).Send()
+			elapsed := time.Since(start)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := resp.Status().Code(); got != http.StatusOK {
+				t.Errorf("status got %d, want %d", got, http.StatusOK)
+			}
+			if !tt.expectedDelay(elapsed) {
+				t.Errorf("elapsed %v outside the expected range", elapsed)
+			}
+		})
+	}
+}
+
+func TestRequest_calculateRetryDelayServerCap(t *testing.T) {
+	tests := []struct {
+		name          string
+		retryAfter    string
+		maxDelay      time.Duration
+		expectedDelay time.Duration
+	}{
+		{
+			name:          "Server delay within the default cap",
+			retryAfter:    "30",
+			expectedDelay: 30 * time.Second,
+		},
+		{
+			name:          "Server delay capped by default",
+			retryAfter:    "9223372036854775807",
+			expectedDelay: defaultMaxRetryAfterDelay,
+		},
+		{
+			name:          "Max delay replaces the default cap",
+			retryAfter:    "3600",
+			maxDelay:      time.Hour,
+			expectedDelay: time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			rb := DefaultClient("https://example.com").GET("/test").
+				Retry().SetConstantBackoff(time.Millisecond, 2).
+				Retry().WithRetryAfter()
+			if tt.maxDelay > 0 {
+				rb.Retry().WithMaxDelay(tt.maxDelay)
+			}
+			response := newResponse(&http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{header.RetryAfter.String(): {tt.retryAfter}},
+			})
+
+			// Act
+			got := rb.calculateRetryDelay(0, 0, response)
+
+			// Assert
+			if got != tt.expectedDelay {
+				t.Errorf("got %v, want %v", got, tt.expectedDelay)
+			}
+		})
+	}
+}
+
+func TestRequest_RetryPredicate(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           method.Type
+		predicate        RetryPredicate
+		transportErrors  []error
+		expectedAttempts int
+		expectError      bool
+	}{
+		{
+			name:             "Network errors are retried",
+			method:           method.GET,
+			predicate:        RetryOnNetworkErrors,
+			transportErrors:  []error{syscall.ECONNRESET, syscall.ECONNREFUSED},
+			expectedAttempts: 3,
+		},
+		{
+			name:             "TLS verification errors fail fast",
+			method:           method.GET,
+			predicate:        RetryOnNetworkErrors,
+			transportErrors:  []error{x509.UnknownAuthorityError{}},
+			expectedAttempts: 1,
+			expectError:      true,
+		},
+		{
+			name:             "Non-idempotent methods are not retried",
+			method:           method.POST,
+			predicate:        RetryOnAll(RetryOnIdempotentMethods, RetryOnNetworkErrors),
+			transportErrors:  []error{syscall.ECONNRESET},
+			expectedAttempts: 1,
+			expectError:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			attemptCount := 0
+			var attempts []RetryAttempt
+			mockClient := &mock.HttpClientComponent{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					defer func() { attemptCount++ }()
+					if attemptCount < len(tt.transportErrors) {
+						return nil, &url.Error{Op: req.Method, URL: req.URL.String(), Err: tt.transportErrors[attemptCount]}
+					}
+					return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: http.Header{}, Request: req}, nil
+				},
+			}
+			client := NewClient("https://example.com").
+				Config().SetCustomHttpClient(mockClient).
+				client
+
+			// Act
+			resp, err := newRequest(client, tt.method, "/test").
+				Retry().SetConstantBackoff(time.Millisecond, 5).
+				Retry().WithRetryPredicate(func(response *Response, err error, attempt RetryAttempt) bool {
+				attempts = append(attempts, attempt)
+				return tt.predicate(response, err, attempt)
+			}).
+				Send()
+
+			// Assert
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if resp != nil {
+					t.Errorf("resp got %v, want nil", resp)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if attemptCount != tt.expectedAttempts {
+				t.Errorf("attempts got %d, want %d", attemptCount, tt.expectedAttempts)
+			}
+			for i, attempt := range attempts {
+				if attempt.Number != uint(i+1) {
+					t.Errorf("attempt number got %d, want %d", attempt.Number, i+1)
+				}
+				if attempt.MaxAttempts != 5 {
+					t.Errorf("max attempts got %d, want 5", attempt.MaxAttempts)
+				}
+				if attempt.Request == nil {
+					t.Error("attempt request got nil, want non-nil")
+				}
+			}
+		})
+	}
+}
+
+func TestRequest_ClientRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name             string
+		configure        func(*RequestBuilder) *RequestBuilder
+		expectedAttempts int32
+	}{
+		{
+			name:             "Client policy applies to request",
+			configure:        func(rb *RequestBuilder) *RequestBuilder { return rb },
+			expectedAttempts: 3,
+		},
+		{
+			name: "Request overrides client policy",
+			configure: func(rb *RequestBuilder) *RequestBuilder {
+				return rb.Retry().SetConstantBackoff(time.Millisecond, 2)
+			},
+			expectedAttempts: 2,
+		},
+		{
+			name: "Request disables client policy",
+			configure: func(rb *RequestBuilder) *RequestBuilder {
+				return rb.Retry().Disable()
+			},
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var attemptCount atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attemptCount.Add(1)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL).
+				Retry().SetConstantBackoff(time.Millisecond, 3).
+				Build()
+
+			// Act
+			_, _ = tt.configure(client.GET("/test")).Send()
+
+			// Assert
+			if got := attemptCount.Load(); got != tt.expectedAttempts {
+				t.Errorf("attempts got %d, want %d", got, tt.expectedAttempts)
+			}
+		})
+	}
+}
+
+func TestRequest_RetryBudget(t *testing.T) {
+	// Arrange
+	var attemptCount atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attemptCount.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL).
+		Retry().SetConstantBackoff(time.Millisecond, 5).
+		Retry().WithBudget(0, 0).
+		Build()
+
+	// Act
+	resp, err := client.GET("/test").Send()
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Errorf("error %q does not wrap ErrRetryBudgetExhausted", err)
+	}
+	if resp != nil {
+		t.Errorf("resp got %v, want nil", resp)
+	}
+	if got := attemptCount.Load(); got != 1 {
+		t.Errorf("attempts got %d, want 1", got)
+	}
+}
+
+func TestRequest_PerAttemptTimeout(t *testing.T) {
+	t.Run("Hung attempt is retried", func(t *testing.T) {
+		// Arrange
+		var attemptCount atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attemptCount.Add(1) == 1 {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(5 * time.Second):
+				}
+				return
+			}
+			_, _ = w.Write([]byte("Success"))
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		// Act
+		resp, err := DefaultClient(server.URL).GET("/test").
+			Context().Set(ctx).
+			Retry().SetConstantBackoff(time.Millisecond, 3).
+			Retry().WithPerAttemptTimeout(50 * time.Millisecond).
+			Send()
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		body, err := resp.Body().AsString()
+		if err != nil {
+			t.Fatalf("unexpected error reading body: %v", err)
+		}
+		if body != "Success" {
+			t.Errorf("body got %q, want %q", body, "Success")
+		}
+		if got := attemptCount.Load(); got != 2 {
+			t.Errorf("attempts got %d, want 2", got)
+		}
+	})
+
+	t.Run("Single attempt honors the timeout", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}))
+		defer server.Close()
+
+		// Act
+		resp, err := DefaultClient(server.URL).GET("/test").
+			Retry().WithPerAttemptTimeout(50 * time.Millisecond).
+			Send()
+
+		// Assert
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("error %q does not wrap context.DeadlineExceeded", err)
+		}
+		if resp != nil {
+			t.Errorf("resp got %v, want nil", resp)
+		}
+	})
+}
+
+func TestRequest_RetryFailover(t *testing.T) {
+	t.Run("Retries move to the next backend", func(t *testing.T) {
+		// Arrange
+		var failingCount atomic.Int32
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			failingCount.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+
+		healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("Success"))
+		}))
+		defer healthy.Close()
+
+		client := NewClientLoadBalancer([]string{failing.URL, failing.URL, healthy.URL}).
+			Retry().SetConstantBackoff(time.Millisecond, 2).
+			Retry().WithFailover().
+			Build()
+
+		// Act
+		for range 6 {
+			resp, err := client.GET("/test").Send()
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := resp.Status().Code(); got != http.StatusOK {
+				t.Errorf("status got %d, want %d", got, http.StatusOK)
+			}
+			resp.Body().Close()
+		}
+	})
+
+	t.Run("Error records the failing backend of each attempt", func(t *testing.T) {
+		// Arrange
+		server1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server1.Close()
+
+		server2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server2.Close()
+
+		client := NewClientLoadBalancer([]string{server1.URL, server2.URL}).
+			Retry().SetConstantBackoff(time.Millisecond, 2).
+			Retry().WithFailover().
+			Build()
+
+		// Act
+		resp, err := client.GET("/test").Send()
+
+		// Assert
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if resp != nil {
+			t.Errorf("resp got %v, want nil", resp)
+		}
+		for _, backend := range []string{server1.URL, server2.URL} {
+			if !strings.Contains(err.Error(), backend) {
+				t.Errorf("error %q does not contain backend %q", err.Error(), backend)
+			}
+		}
+	})
+}
+
+func TestRequest_RetryLifecycleHooks(t *testing.T) {
+	t.Run("Retry hooks fire between attempts", func(t *testing.T) {
+		// Arrange
+		var attemptCount atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attemptCount.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		var order []string
+		var events []RetryEvent
+		client := NewClient(server.URL).
+			Hook().OnRetry(func(req *http.Request, event RetryEvent) {
+			order = append(order, "client")
+			events = append(events, event)
+		}).
+			Build()
+
+		// Act
+		_, err := client.GET("/test").
+			Retry().SetConstantBackoff(5*time.Millisecond, 3).
+			Hook().OnRetry(func(req *http.Request, event RetryEvent) {
+			order = append(order, "request")
+		}).
+			Send()
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expectedOrder := []string{"client", "request", "client", "request"}
+		if !reflect.DeepEqual(order, expectedOrder) {
+			t.Errorf("order got %v, want %v", order, expectedOrder)
+		}
+		for i, event := range events {
+			if event.Attempt != uint(i+1) {
+				t.Errorf("event %d attempt got %d, want %d", i, event.Attempt, i+1)
+			}
+			if event.Delay != 5*time.Millisecond {
+				t.Errorf("event %d delay got %v, want %v", i, event.Delay, 5*time.Millisecond)
+			}
+			if event.Cause == nil || !strings.Contains(event.Cause.Error(), "503") {
+				t.Errorf("event %d cause got %v, want the 503 status", i, event.Cause)
+			}
+		}
+	})
+
+	t.Run("Error and give-up hooks fire on transport errors", func(t *testing.T) {
+		// Arrange
+		transportErr := errors.New("connection reset by peer")
+		mockClient := &mock.HttpClientComponent{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				return nil, transportErr
+			},
+		}
+
+		var errorCount, giveUpCount, afterCount atomic.Int32
+		var giveUpErr error
+		client := NewClient("https://example.com").
+			Config().SetCustomHttpClient(mockClient).
+			Hook().OnError(func(req *http.Request, err error) {
+			if errors.Is(err, transportErr) {
+				errorCount.Add(1)
+			}
+		}).
+			Hook().OnGiveUp(func(req *http.Request, err error) {
+			giveUpCount.Add(1)
+			giveUpErr = err
+		}).
+			Hook().OnAfterResponse(func(req *http.Request, resp *http.Response) {
+			afterCount.Add(1)
+		}).
+			Build()
+
+		// Act
+		_, err := client.GET("/test").
+			Retry().SetConstantBackoff(time.Millisecond, 3).
+			Send()
+
+		// Assert
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if got := errorCount.Load(); got != 3 {
+			t.Errorf("error hook count got %d, want 3", got)
+		}
+		if got := giveUpCount.Load(); got != 1 {
+			t.Errorf("give-up hook count got %d, want 1", got)
+		}
+		if !errors.Is(giveUpErr, err) {
+			t.Errorf("give-up error got %v, want %v", giveUpErr, err)
+		}
+		if got := afterCount.Load(); got != 0 {
+			t.Errorf("after response hook count got %d, want 0", got)
+		}
+	})
+
+	t.Run("Give-up hooks do not fire on success", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		giveUpCalled := false
+		client := NewClient(server.URL).Build()
+
+		// Act
+		_, err := client.GET("/test").
+			Hook().OnGiveUp(func(req *http.Request, err error) {
+			giveUpCalled = true
+		}).
+			Send()
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if giveUpCalled {
+			t.Error("give-up hook was called, want not called")
+		}
+	})
+}
+
+func TestRequest_LoadBalancingStrategy(t *testing.T) {
+	// Arrange
+	var hits1, hits2 atomic.Int32
+	server1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits1.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server1.Close()
+	server2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits2.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server2.Close()
+
+	client := NewClientLoadBalancer([]string{server1.URL, server2.URL}).
+		LoadBalancer().WithStrategy(NewLeastOutstandingStrategy()).
+		Build()
+
+	// Act - keep the first response body open so its backend stays busy
+	held, err := client.GET("/test").Send()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range 3 {
+		resp, err := client.GET("/test").Send()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body().Close()
+	}
+	held.Body().Close()
+	resp, err := client.GET("/test").Send()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body().Close()
+
+	// Assert
+	if got := hits1.Load(); got != 2 {
+		t.Errorf("server1 hits got %d, want 2", got)
+	}
+	if got := hits2.Load(); got != 3 {
+		t.Errorf("server2 hits got %d, want 3", got)
+	}
+}
+
+func TestRequest_HealthCheck(t *testing.T) {
+	// Arrange
+	var healthyHits, unhealthyHits atomic.Int32
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/test" {
+			healthyHits.Add(1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/test" {
+			unhealthyHits.Add(1)
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unhealthy.Close()
+
+	checker := NewHealthChecker(HealthCheckConfig{
+		Path:               "/health",
+		Interval:           5 * time.Millisecond,
+		UnhealthyThreshold: 1,
+	})
+	defer func() { _ = checker.Close() }()
+
+	builder := NewClientLoadBalancer([]string{healthy.URL, unhealthy.URL}).
+		LoadBalancer().WithHealthCheck(checker)
+	client := builder.Build()
+	balancer := builder.client.(*ClientConfigBase).ConfigBaseURL.(*BalancedBaseURL)
+
+	deadline := time.Now().Add(time.Second)
+	for balancer.IsHealthy(balancer.Backends()[1].URL) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Act
+	for range 4 {
+		resp, err := client.GET("/test").Send()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body().Close()
+	}
+
+	// Assert
+	if got := healthyHits.Load(); got != 4 {
+		t.Errorf("healthy server hits got %d, want 4", got)
+	}
+	if got := unhealthyHits.Load(); got != 0 {
+		t.Errorf("unhealthy server hits got %d, want 0", got)
+	}
+}
+
+func TestRequest_OutlierDetection(t *testing.T) {
+	// Arrange
+	var healthyHits, failingHits atomic.Int32
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		healthyHits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		failingHits.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	client := NewClientLoadBalancer([]string{failing.URL, healthy.URL}).
+		LoadBalancer().WithOutlierDetection(OutlierDetectionConfig{
+		ConsecutiveFailures: 2,
+		BaseEjectionTime:    time.Minute,
+		MaxEjectionPercent:  50,
+	}).
+		Build()
+
+	// Act
+	for range 10 {
+		resp, err := client.GET("/test").Send()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body().Close()
+	}
+
+	// Assert
+	if got := failingHits.Load(); got != 2 {
+		t.Errorf("failing server hits got %d, want 2", got)
+	}
+	if got := healthyHits.Load(); got != 8 {
+		t.Errorf("healthy server hits got %d, want 8", got)
+	}
+}
+
+func TestRequest_ConsistentHashing(t *testing.T) {
+	// Arrange
+	hits := make([]atomic.Int32, 4)
+	var baseURLs []string
+	for i := range hits {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits[i].Add(1)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+		baseURLs = append(baseURLs, server.URL)
+	}
+
+	client := NewClientLoadBalancer(baseURLs).
+		LoadBalancer().WithStrategy(NewConsistentHashStrategy()).
+		LoadBalancer().WithHashKey(HashKeyFromHeader("X-Tenant-ID")).
+		Build()
+
+	served := func() int {
+		for i := range hits {
+			if hits[i].Swap(0) > 0 {
+				return i
+			}
+		}
+		return -1
+	}
+	send := func(builder *RequestBuilder) int {
+		resp, err := builder.Send()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body().Close()
+		return served()
+	}
+
+	// Act
+	fromHeader := send(client.GET("/test").Header().Set("X-Tenant-ID", "acme"))
+	fromKey := send(client.GET("/test").LoadBalancer().WithHashKey("acme"))
+
+	// Assert
+	for range 5 {
+		if got := send(client.GET("/test").Header().Set("X-Tenant-ID", "acme")); got != fromHeader {
+			t.Errorf("header keyed request served by %d, want %d", got, fromHeader)
+		}
+		if got := send(client.GET("/test").Header().Set("X-Tenant-ID", "globex").LoadBalancer().WithHashKey("acme")); got != fromKey {
+			t.Errorf("explicitly keyed request served by %d, want %d", got, fromKey)
+		}
+	}
+	if fromHeader != fromKey {
+		t.Errorf("same key served by %d and %d, want the same backend", fromHeader, fromKey)
+	}
+}
+
+func TestRequest_DynamicMembership(t *testing.T) {
+	t.Run("Client follows endpoint updates", func(t *testing.T) {
+		// Arrange
+		var hits1, hits2 atomic.Int32
+		server1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits1.Add(1)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server1.Close()
+		server2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits2.Add(1)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server2.Close()
+
+		endpoints := NewEndpointSet([]Endpoint{{BaseURL: server1.URL}})
+		client := NewClientLoadBalancerFromEndpoints(endpoints).Build()
+		send := func() {
+			resp, err := client.GET("/test").Send()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body().Close()
+		}
+
+		// Act
+		send()
+		if err := endpoints.Replace([]Endpoint{{BaseURL: server2.URL}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		send()
+		send()
+
+		// Assert
+		if got := hits1.Load(); got != 1 {
+			t.Errorf("server1 hits got %d, want 1", got)
+		}
+		if got := hits2.Load(); got != 2 {
+			t.Errorf("server2 hits got %d, want 2", got)
+		}
+	})
+
+	t.Run("Drain waits for requests in flight", func(t *testing.T) {
+		// Arrange
+		server1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server1.Close()
+		server2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server2.Close()
+
+		endpoints := NewEndpointSet([]Endpoint{{BaseURL: server1.URL}, {BaseURL: server2.URL}})
+		client := NewClientLoadBalancerFromEndpoints(endpoints).Build()
+		held, err := client.GET("/test").Send()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Act
+		drained := make(chan error, 1)
+		go func() {
+			drained <- endpoints.Drain(context.Background(), server1.URL)
+		}()
+
+		// Assert
+		select {
+		case err := <-drained:
+			t.Fatalf("drain returned %v while a request was in flight", err)
+		case <-time.After(20 * time.Millisecond):
+		}
+		held.Body().Close()
+		select {
+		case err := <-drained:
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("drain did not return after the request finished")
+		}
+	})
+
+	t.Run("Client without endpoints fails requests", func(t *testing.T) {
+		// Arrange
+		client := NewClientLoadBalancerFromEndpoints(NewEndpointSet(nil)).Build()
+
+		// Act
+		_, err := client.GET("/test").Send()
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), constant.ErrMsgEmptyBaseURL) {
+			t.Errorf("got %v, want %s error", err, constant.ErrMsgEmptyBaseURL)
+		}
+	})
+}
+
+func TestRequest_ZoneAwareFailover(t *testing.T) {
+	// Arrange
+	var localHits, remoteHits atomic.Int32
+	local := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		localHits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer local.Close()
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remoteHits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer remote.Close()
+
+	client := NewClientWeightedLoadBalancer([]Endpoint{
+		{BaseURL: remote.URL, Zone: "eu-2"},
+		{BaseURL: local.URL, Zone: "eu-1"},
+	}).
+		LoadBalancer().WithPriorityGroups(PriorityGroupsConfig{LocalZone: "eu-1"}).
+		Retry().SetConstantBackoff(time.Millisecond, 2).
+		Retry().WithFailover().
+		Build()
+
+	// Act
+	resp, err := client.GET("/test").Send()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body().Close()
+	if got := resp.Status().Code(); got != http.StatusOK {
+		t.Errorf("status got %d, want %d", got, http.StatusOK)
+	}
+	if got := localHits.Load(); got != 1 {
+		t.Errorf("local server hits got %d, want 1", got)
+	}
+	if got := remoteHits.Load(); got != 1 {
+		t.Errorf("remote server hits got %d, want 1", got)
+	}
+}
+
+func TestRequest_CircuitBreaker(t *testing.T) {
+	// Arrange
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var giveUps atomic.Int32
+	client := NewClient(server.URL).
+		CircuitBreaker().Enable(CircuitBreakerConfig{ConsecutiveFailures: 2, Cooldown: time.Minute}).
+		Hook().OnGiveUp(func(_ *http.Request, _ error) { giveUps.Add(1) }).
+		Build()
+
+	// Act
+	for range 2 {
+		resp, err := client.GET("/test").Send()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body().Close()
+	}
+	resp, err := client.GET("/test").Send()
+
+	// Assert
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("error got %v, want %v", err, ErrCircuitOpen)
+	}
+	if resp != nil {
+		t.Errorf("response got %v, want nil", resp)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("server hits got %d, want 2", got)
+	}
+	if got := giveUps.Load(); got != 1 {
+		t.Errorf("give-up hooks got %d calls, want 1", got)
+	}
+}
+
+func TestRequest_CircuitBreakerOpensBetweenRetries(t *testing.T) {
+	// Arrange
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var giveUps atomic.Int32
+	var giveUpRequest *http.Request
+	onGiveUp := func(req *http.Request, _ error) {
+		giveUps.Add(1)
+		giveUpRequest = req
+	}
+	client := NewClient(server.URL).
+		CircuitBreaker().Enable(CircuitBreakerConfig{ConsecutiveFailures: 1, Cooldown: time.Minute}).
+		Hook().OnGiveUp(onGiveUp).
+		Build()
+
+	// Act
+	resp, err := client.GET("/test").
+		Retry().SetConstantBackoff(time.Millisecond, 3).
+		Retry().WithRetryCondition(func(r *Response) bool { return r.Status().Is5xxServerError() }).
+		Send()
+
+	// Assert
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("error got %v, want %v", err, ErrCircuitOpen)
+	}
+	if err == nil || !strings.Contains(err.Error(), "attempt 1") {
+		t.Errorf("error got %v, want the error of attempt 1", err)
+	}
+	if resp != nil {
+		t.Errorf("response got %v, want nil", resp)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("server hits got %d, want 1", got)
+	}
+	if got := giveUps.Load(); got != 1 {
+		t.Errorf("give-up hooks got %d calls, want 1", got)
+	}
+	if giveUpRequest == nil || giveUpRequest.URL.Path != "/test" {
+		t.Errorf("give-up request got %v, want the request to /test", giveUpRequest)
+	}
+}
+
+func TestRequest_CircuitBreakerLoadBalanced(t *testing.T) {
+	// Arrange
+	var healthyHits, failingHits atomic.Int32
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		healthyHits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		failingHits.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	client := NewClientLoadBalancer([]string{failing.URL, healthy.URL}).
+		CircuitBreaker().Enable(CircuitBreakerConfig{ConsecutiveFailures: 1, Cooldown: time.Minute}).
+		Build()
+
+	// Act
+	for range 10 {
+		resp, err := client.GET("/test").Send()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body().Close()
+	}
+
+	// Assert
+	if got := failingHits.Load(); got != 1 {
+		t.Errorf("failing server hits got %d, want 1", got)
+	}
+	if got := healthyHits.Load(); got != 9 {
+		t.Errorf("healthy server hits got %d, want 9", got)
+	}
+}
+
+func TestRequest_RateLimit(t *testing.T) {
+	// Arrange
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var giveUps atomic.Int32
+	client := NewClient(server.URL).
+		RateLimit().Set(1, 2).
+		Hook().OnGiveUp(func(_ *http.Request, _ error) { giveUps.Add(1) }).
+		Build()
+
+	// Act
+	for range 2 {
+		resp, err := client.GET("/test").Send()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body().Close()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	resp, err := client.GET("/test").Context().Set(ctx).Send()
+
+	// Assert
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error got %v, want %v", err, context.DeadlineExceeded)
+	}
+	if resp != nil {
+		t.Errorf("response got %v, want nil", resp)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("server hits got %d, want 2", got)
+	}
+	if got := giveUps.Load(); got != 1 {
+		t.Errorf("give-up hooks got %d calls, want 1", got)
+	}
+}
+
+func TestRequest_Bulkhead(t *testing.T) {
+	// Arrange
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var giveUps atomic.Int32
+	client := NewClient(server.URL).
+		Bulkhead().Enable(BulkheadConfig{MaxConcurrent: 1}).
+		Hook().OnGiveUp(func(_ *http.Request, _ error) { giveUps.Add(1) }).
+		Build()
+	held, err := client.GET("/test").Send()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	rejected, errRejected := client.GET("/test").Send()
+	held.Body().Close()
+	accepted, errAccepted := client.GET("/test").Send()
+
+	// Assert
+	var bulkheadErr *BulkheadError
+	if !errors.As(errRejected, &bulkheadErr) {
+		t.Fatalf("error got %v, want a BulkheadError", errRejected)
+	}
+	if rejected != nil {
+		t.Errorf("response got %v, want nil", rejected)
+	}
+	if errAccepted != nil {
+		t.Fatalf("unexpected error after the slot was freed: %v", errAccepted)
+	}
+	accepted.Body().Close()
+	if got := hits.Load(); got != 2 {
+		t.Errorf("server hits got %d, want 2", got)
+	}
+	if got := giveUps.Load(); got != 1 {
+		t.Errorf("give-up hooks got %d calls, want 1", got)
+	}
+}
+
+func TestRequest_BulkheadFullBetweenRetries(t *testing.T) {
+	// Arrange
+	var hits atomic.Int32
+	held := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/held" {
+			close(held)
+			<-release
+			return
+		}
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	var giveUps atomic.Int32
+	var client ClientHttpMethods
+	onRetry := func(_ *http.Request, _ RetryEvent) {
+		// Take the only slot before the next attempt
+		go func() { _, _ = client.GET("/held").Send() }()
+		<-held
+	}
+	client = NewClient(server.URL).
+		Bulkhead().Enable(BulkheadConfig{MaxConcurrent: 1}).
+		Hook().OnRetry(onRetry).
+		Hook().OnGiveUp(func(_ *http.Request, _ error) { giveUps.Add(1) }).
+		Build()
+
+	// Act
+	resp, err := client.GET("/test").
+		Retry().SetConstantBackoff(time.Millisecond, 3).
+		Retry().WithRetryCondition(func(r *Response) bool { return r.Status().Is5xxServerError() }).
+		Send()
+
+	// Assert
+	if !errors.Is(err, ErrBulkheadFull) {
+		t.Errorf("error got %v, want %v", err, ErrBulkheadFull)
+	}
+	if err == nil || !strings.Contains(err.Error(), "attempt 1") {
+		t.Errorf("error got %v, want the error of attempt 1", err)
+	}
+	if resp != nil {
+		t.Errorf("response got %v, want nil", resp)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("server hits got %d, want 1", got)
+	}
+	if got := giveUps.Load(); got != 1 {
+		t.Errorf("give-up hooks got %d calls, want 1", got)
+	}
+}
+
+func TestRequest_Hedging(t *testing.T) {
+	t.Run("Hedged attempt on another backend wins", func(t *testing.T) {
+		// Arrange
+		var hits atomic.Int32
+		var slowHost atomic.Value
+		canceled := make(chan struct{})
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hits.Add(1) == 1 {
+				slowHost.Store(r.Host)
+				<-r.Context().Done()
+				close(canceled)
+				return
+			}
+			_, _ = w.Write([]byte(r.Host))
+		})
+		server1 := httptest.NewServer(handler)
+		defer server1.Close()
+		server2 := httptest.NewServer(handler)
+		defer server2.Close()
+
+		var errorHooks atomic.Int32
+		client := NewClientLoadBalancer([]string{server1.URL, server2.URL}).
+			Hook().OnError(func(_ *http.Request, _ error) { errorHooks.Add(1) }).
+			Build()
+
+		// Act
+		response, err := client.GET("/test").
+			Hedge().SetFixedDelay(20*time.Millisecond, 2).
+			Send()
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer response.Body().Close()
+		host, err := response.Body().AsString()
+		if err != nil {
+			t.Fatalf("unexpected error reading body: %v", err)
+		}
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Fatal("slow attempt was not canceled")
+		}
+		if host == slowHost.Load() {
+			t.Errorf("hedged attempt got host %s, want another backend", host)
+		}
+		if got := hits.Load(); got != 2 {
+			t.Errorf("server hits got %d, want 2", got)
+		}
+		if got := errorHooks.Load(); got != 0 {
+			t.Errorf("error hooks got %d calls, want 0", got)
+		}
+	})
+
+	t.Run("Fast response is not hedged", func(t *testing.T) {
+		// Arrange
+		var hits atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		tracker := NewLatencyTracker(1, time.Minute)
+		client := NewClient(server.URL).Build()
+
+		// Act
+		response, err := client.GET("/test").
+			Hedge().SetPercentileDelay(tracker, 95, 1).
+			Send()
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		response.Body().Close()
+		if got := hits.Load(); got != 1 {
+			t.Errorf("server hits got %d, want 1", got)
+		}
+		if got := tracker.Percentile(95); got >= time.Minute {
+			t.Errorf("tracked latency got %v, want the observed latency", got)
+		}
+	})
+
+	t.Run("Non-idempotent method", func(t *testing.T) {
+		// Arrange
+		client := NewClient("https://example.com").Build()
+
+		// Act
+		response, err := client.POST("/test").
+			Hedge().SetFixedDelay(time.Millisecond, 1).
+			Send()
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), constant.ErrMsgHedgeNonIdempotent) {
+			t.Errorf("error got %v, want %q", err, constant.ErrMsgHedgeNonIdempotent)
+		}
+		if response != nil {
+			t.Errorf("response got %v, want nil", response)
+		}
+	})
+}
+
+func TestRequest_Coalescing(t *testing.T) {
+	tests := []struct {
+		name  string
+		vary  string
+		apply func(rb *RequestBuilder, value string) *RequestBuilder
+		read  func(r *http.Request) string
+	}{
+		{
+			name: "Vary header",
+			vary: "Authorization",
+			apply: func(rb *RequestBuilder, value string) *RequestBuilder {
+				return rb.Header().Add("Authorization", value)
+			},
+			read: func(r *http.Request) string {
+				return r.Header.Get("Authorization")
+			},
+		},
+		{
+			name: "Vary cookie",
+			vary: "Cookie",
+			apply: func(rb *RequestBuilder, value string) *RequestBuilder {
+				return rb.Cookie().Add(&http.Cookie{Name: "session", Value: value})
+			},
+			read: func(r *http.Request) string {
+				cookie, _ := r.Cookie("session")
+				return cookie.Value
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var hits atomic.Int32
+			release := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits.Add(1)
+				<-release
+				_, _ = w.Write([]byte(tt.read(r)))
+			}))
+			defer server.Close()
+			var unblock sync.Once
+			defer unblock.Do(func() { close(release) })
+
+			coalescer := NewCoalescer(CoalescingConfig{VaryHeaders: []string{tt.vary}})
+			client := NewClient(server.URL).
+				Coalescing().SetCustom(coalescer).
+				Build()
+			request := func(value string) *RequestBuilder {
+				return tt.apply(client.GET("/config"), value)
+			}
+			send := func(value string) <-chan string {
+				bodies := make(chan string, 1)
+				go func() {
+					response, err := request(value).Send()
+					if err != nil {
+						t.Errorf("unexpected error: %v", err)
+						bodies <- ""
+						return
+					}
+					defer response.Body().Close()
+					body, _ := response.Body().AsString()
+					bodies <- body
+				}()
+				return bodies
+			}
+			key := func(value string) string {
+				key, err := request(value).coalescingKey(context.Background(), coalescer)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return key
+			}
+
+			// Act
+			first := send("alice")
+			second := send("alice")
+			other := send("bob")
+			waitForCallers(t, coalescer, key("alice"), 2)
+			waitForCallers(t, coalescer, key("bob"), 1)
+			unblock.Do(func() { close(release) })
+
+			// Assert
+			for name, bodies := range map[string]<-chan string{"first": first, "second": second} {
+				if got := <-bodies; got != "alice" {
+					t.Errorf("%s body got %q, want %q", name, got, "alice")
+				}
+			}
+			if got := <-other; got != "bob" {
+				t.Errorf("other body got %q, want %q", got, "bob")
+			}
+			if got := hits.Load(); got != 2 {
+				t.Errorf("server hits got %d, want 2", got)
+			}
+		})
+	}
+}
+
+func TestRequest_coalescingKey(t *testing.T) {
+	balanced := NewClientLoadBalancer([]string{"https://api1.example.com", "https://api2.example.com"}).
+		LoadBalancer().WithHashKey(HashKeyFromQuery("tenant")).
+		Build()
+	single := NewClient("https://api.example.com").Build()
+
+	tests := []struct {
+		name     string
+		request  *RequestBuilder
+		other    *RequestBuilder
+		expected bool
+	}{
+		{
+			name:     "Single base URL",
+			request:  single.GET("/config").Query().AddParam("env", "prod"),
+			other:    single.GET("/config").Query().AddParam("env", "prod"),
+			expected: true,
+		},
+		{
+			name:     "Single base URL clients with the same base URL",
+			request:  single.GET("/config"),
+			other:    NewClient("https://api.example.com").Build().GET("/config"),
+			expected: true,
+		},
+		{
+			name:     "Single base URL clients with different base URLs",
+			request:  single.GET("/config"),
+			other:    NewClient("https://other.example.com").Build().GET("/config"),
+			expected: false,
+		},
+		{
+			name:     "Load-balanced base URLs with the same hash key",
+			request:  balanced.GET("/config").Query().AddParam("tenant", "a"),
+			other:    balanced.GET("/config").Query().AddParam("tenant", "a"),
+			expected: true,
+		},
+		{
+			name:     "Load-balanced base URLs with different hash keys",
+			request:  balanced.GET("/config").LoadBalancer().WithHashKey("a"),
+			other:    balanced.GET("/config").LoadBalancer().WithHashKey("b"),
+			expected: false,
+		},
+		{
+			name:     "Load-balanced clients with other backends",
+			request:  balanced.GET("/config"),
+			other:    NewClientLoadBalancer([]string{"https://api3.example.com", "https://api4.example.com"}).Build().GET("/config"),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			coalescer := NewCoalescer(CoalescingConfig{})
+
+			// Act
+			key, err := tt.request.coalescingKey(context.Background(), coalescer)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			other, err := tt.other.coalescingKey(context.Background(), coalescer)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Assert
+			if got := key == other; got != tt.expected {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
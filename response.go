@@ -1,9 +1,14 @@
 package fastshot
 
 import (
+	"io"
 	"net/http"
+	"sync"
 )
 
+// maxDiscardBytes limits how much of a discarded response body is drained before closing it.
+const maxDiscardBytes = 64 << 10
+
 type Response struct {
 	rawResponse *http.Response
 	// Fluent API
@@ -18,6 +23,35 @@ func (r *Response) Raw() *http.Response {
 	return r.rawResponse
 }
 
+// discard drains and closes the response body so the underlying connection can be reused.
+func (r *Response) discard() {
+	if r.rawResponse == nil || r.rawResponse.Body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(r.rawResponse.Body, maxDiscardBytes))
+	_ = r.rawResponse.Body.Close()
+}
+
+// onBodyClose registers fn to run once the response body is closed.
+func (r *Response) onBodyClose(fn func()) {
+	body := &callbackOnCloseBody{ReadCloser: r.rawResponse.Body, callback: fn}
+	r.rawResponse.Body = body
+	r.body = &ResponseFluentBody{newUnbufferedBody(body)}
+}
+
+// callbackOnCloseBody wraps a response body and runs a callback when it is closed.
+type callbackOnCloseBody struct {
+	io.ReadCloser
+	callback func()
+	once     sync.Once
+}
+
+func (b *callbackOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.callback)
+	return err
+}
+
 func newResponse(response *http.Response) *Response {
 	return &Response{
 		rawResponse: response,
@@ -0,0 +1,71 @@
+package fastshot
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opus-domini/fast-shot/constant/header"
+)
+
+const (
+	// unixTimestampThreshold separates X-RateLimit-Reset values sent as Unix timestamps from
+	// values sent as delta seconds. Any value above it (2001-09-09) is treated as a timestamp.
+	unixTimestampThreshold = 1_000_000_000
+	// defaultMaxRetryAfterDelay caps the retry delays requested by the server when no maximum
+	// delay is set, so a misbehaving server cannot stall a request indefinitely.
+	defaultMaxRetryAfterDelay = 5 * time.Minute
+)
+
+// retryAfterDelay returns the delay requested by the server through the Retry-After,
+// RateLimit-Reset or X-RateLimit-Reset headers, in that order of precedence.
+func retryAfterDelay(httpHeader http.Header, now time.Time) (time.Duration, bool) {
+	if value := strings.TrimSpace(httpHeader.Get(header.RetryAfter.String())); value != "" {
+		// Retry-After is either delay-seconds or an HTTP-date
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return secondsToDelay(seconds), true
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			return nonNegative(date.Sub(now)), true
+		}
+	}
+
+	if value := strings.TrimSpace(httpHeader.Get(header.RateLimitReset.String())); value != "" {
+		// RateLimit-Reset is always delta-seconds
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return secondsToDelay(seconds), true
+		}
+	}
+
+	if value := strings.TrimSpace(httpHeader.Get(header.XRateLimitReset.String())); value != "" {
+		// X-RateLimit-Reset is either delta-seconds or a Unix timestamp, depending on the vendor
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+			if seconds > unixTimestampThreshold {
+				return nonNegative(time.Unix(seconds, 0).Sub(now)), true
+			}
+			return secondsToDelay(seconds), true
+		}
+	}
+
+	return 0, false
+}
+
+// secondsToDelay converts delta seconds to a delay, saturating instead of overflowing.
+func secondsToDelay(seconds int64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	if seconds > int64(math.MaxInt64/time.Second) {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func nonNegative(delay time.Duration) time.Duration {
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
@@ -0,0 +1,109 @@
+package fastshot
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRetryAfterDelay(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		header        http.Header
+		expectedDelay time.Duration
+		expectedOK    bool
+	}{
+		{
+			name:       "No headers",
+			header:     http.Header{},
+			expectedOK: false,
+		},
+		{
+			name:          "Retry-After in seconds",
+			header:        http.Header{"Retry-After": {"120"}},
+			expectedDelay: 2 * time.Minute,
+			expectedOK:    true,
+		},
+		{
+			name:          "Retry-After as HTTP-date",
+			header:        http.Header{"Retry-After": {now.Add(30 * time.Second).Format(http.TimeFormat)}},
+			expectedDelay: 30 * time.Second,
+			expectedOK:    true,
+		},
+		{
+			name:          "Retry-After date in the past",
+			header:        http.Header{"Retry-After": {now.Add(-time.Minute).Format(http.TimeFormat)}},
+			expectedDelay: 0,
+			expectedOK:    true,
+		},
+		{
+			name:       "Invalid Retry-After",
+			header:     http.Header{"Retry-After": {"soon"}},
+			expectedOK: false,
+		},
+		{
+			name:          "RateLimit-Reset in seconds",
+			header:        http.Header{"Ratelimit-Reset": {"5"}},
+			expectedDelay: 5 * time.Second,
+			expectedOK:    true,
+		},
+		{
+			name:          "X-RateLimit-Reset in seconds",
+			header:        http.Header{"X-Ratelimit-Reset": {"7"}},
+			expectedDelay: 7 * time.Second,
+			expectedOK:    true,
+		},
+		{
+			name:          "X-RateLimit-Reset as Unix timestamp",
+			header:        http.Header{"X-Ratelimit-Reset": {strconv.FormatInt(now.Add(45*time.Second).Unix(), 10)}},
+			expectedDelay: 45 * time.Second,
+			expectedOK:    true,
+		},
+		{
+			name: "Retry-After takes precedence",
+			header: http.Header{
+				"Retry-After":     {"1"},
+				"Ratelimit-Reset": {"10"},
+			},
+			expectedDelay: time.Second,
+			expectedOK:    true,
+		},
+		{
+			name:          "Negative value clamps to zero",
+			header:        http.Header{"Retry-After": {"-3"}},
+			expectedDelay: 0,
+			expectedOK:    true,
+		},
+		{
+			name:          "Huge value saturates",
+			header:        http.Header{"Retry-After": {"9223372036854775807"}},
+			expectedDelay: time.Duration(math.MaxInt64),
+			expectedOK:    true,
+		},
+		{
+			name:          "Huge negative value clamps to zero",
+			header:        http.Header{"Retry-After": {"-9223372036854775807"}},
+			expectedDelay: 0,
+			expectedOK:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			delay, ok := retryAfterDelay(tt.header, now)
+
+			// Assert
+			if ok != tt.expectedOK {
+				t.Errorf("ok got %v, want %v", ok, tt.expectedOK)
+			}
+			if delay != tt.expectedDelay {
+				t.Errorf("delay got %v, want %v", delay, tt.expectedDelay)
+			}
+		})
+	}
+}
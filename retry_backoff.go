@@ -0,0 +1,176 @@
+package fastshot
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Compile-time checks that the built-in strategies implement BackoffStrategy.
+var (
+	_ BackoffStrategy = (*ConstantBackoff)(nil)
+	_ BackoffStrategy = (*LinearBackoff)(nil)
+	_ BackoffStrategy = (*ExponentialBackoff)(nil)
+	_ BackoffStrategy = (*FibonacciBackoff)(nil)
+	_ BackoffStrategy = (*FullJitterBackoff)(nil)
+	_ BackoffStrategy = (*EqualJitterBackoff)(nil)
+	_ BackoffStrategy = (*DecorrelatedJitterBackoff)(nil)
+)
+
+type (
+	// BackoffState carries the information a BackoffStrategy needs to compute the next delay.
+	BackoffState struct {
+		// Attempt is the zero-based index of the attempt that just failed.
+		Attempt uint
+		// PreviousDelay is the delay waited before the failed attempt, zero after the first attempt.
+		PreviousDelay time.Duration
+		// MaxDelay is the upper bound set through WithMaxDelay, zero when unbounded.
+		MaxDelay time.Duration
+	}
+
+	// ConstantBackoff waits the same interval between every attempt.
+	ConstantBackoff struct {
+		interval time.Duration
+	}
+
+	// LinearBackoff increases the delay by a fixed increment after every attempt.
+	LinearBackoff struct {
+		interval  time.Duration
+		increment time.Duration
+	}
+
+	// ExponentialBackoff multiplies the delay by a backoff rate after every attempt.
+	ExponentialBackoff struct {
+		interval    time.Duration
+		backoffRate float64
+	}
+
+	// FibonacciBackoff grows the delay following the Fibonacci sequence (1, 1, 2, 3, 5, ...).
+	FibonacciBackoff struct {
+		interval time.Duration
+	}
+
+	// FullJitterBackoff picks a random delay between zero and the delay of the wrapped strategy.
+	FullJitterBackoff struct {
+		strategy BackoffStrategy
+	}
+
+	// EqualJitterBackoff keeps half of the wrapped strategy delay and randomizes the other half.
+	EqualJitterBackoff struct {
+		strategy BackoffStrategy
+	}
+
+	// DecorrelatedJitterBackoff picks a random delay between the base interval and three times
+	// the previous delay, as described in the AWS Architecture Blog "Exponential Backoff And Jitter".
+	DecorrelatedJitterBackoff struct {
+		base time.Duration
+	}
+)
+
+// NewConstantBackoff creates a BackoffStrategy that always waits the given interval.
+func NewConstantBackoff(interval time.Duration) *ConstantBackoff {
+	return &ConstantBackoff{interval: interval}
+}
+
+// NextDelay for ConstantBackoff returns the configured interval.
+func (s *ConstantBackoff) NextDelay(BackoffState) time.Duration {
+	return s.interval
+}
+
+// NewLinearBackoff creates a BackoffStrategy that waits interval + attempt*increment.
+func NewLinearBackoff(interval, increment time.Duration) *LinearBackoff {
+	return &LinearBackoff{interval: interval, increment: increment}
+}
+
+// NextDelay for LinearBackoff returns the interval increased once per failed attempt.
+func (s *LinearBackoff) NextDelay(state BackoffState) time.Duration {
+	return durationFromFloat(float64(s.interval) + float64(s.increment)*float64(state.Attempt))
+}
+
+// NewExponentialBackoff creates a BackoffStrategy that waits interval * backoffRate^attempt.
+func NewExponentialBackoff(interval time.Duration, backoffRate float64) *ExponentialBackoff {
+	return &ExponentialBackoff{interval: interval, backoffRate: backoffRate}
+}
+
+// NextDelay for ExponentialBackoff returns the interval multiplied by the backoff rate per failed attempt.
+func (s *ExponentialBackoff) NextDelay(state BackoffState) time.Duration {
+	return durationFromFloat(float64(s.interval) * math.Pow(s.backoffRate, float64(state.Attempt)))
+}
+
+// NewFibonacciBackoff creates a BackoffStrategy that waits interval * fibonacci(attempt+1).
+func NewFibonacciBackoff(interval time.Duration) *FibonacciBackoff {
+	return &FibonacciBackoff{interval: interval}
+}
+
+// NextDelay for FibonacciBackoff returns the interval multiplied by the next Fibonacci number.
+func (s *FibonacciBackoff) NextDelay(state BackoffState) time.Duration {
+	previous, current := 0.0, 1.0
+	for range state.Attempt {
+		previous, current = current, previous+current
+	}
+	return durationFromFloat(float64(s.interval) * current)
+}
+
+// NewFullJitterBackoff wraps a BackoffStrategy and randomizes its delay over [0, delay).
+func NewFullJitterBackoff(strategy BackoffStrategy) *FullJitterBackoff {
+	return &FullJitterBackoff{strategy: strategy}
+}
+
+// NextDelay for FullJitterBackoff returns a random delay up to the capped delay of the wrapped strategy.
+func (s *FullJitterBackoff) NextDelay(state BackoffState) time.Duration {
+	delay := capDelay(s.strategy.NextDelay(state), state.MaxDelay)
+	return time.Duration(rand.Float64() * float64(delay))
+}
+
+// NewEqualJitterBackoff wraps a BackoffStrategy and randomizes its delay over [delay/2, delay).
+func NewEqualJitterBackoff(strategy BackoffStrategy) *EqualJitterBackoff {
+	return &EqualJitterBackoff{strategy: strategy}
+}
+
+// NextDelay for EqualJitterBackoff returns half of the capped delay plus a random share of the other half.
+func (s *EqualJitterBackoff) NextDelay(state BackoffState) time.Duration {
+	half := capDelay(s.strategy.NextDelay(state), state.MaxDelay) / 2
+	return half + time.Duration(rand.Float64()*float64(half))
+}
+
+// NewDecorrelatedJitterBackoff creates a BackoffStrategy that waits a random delay between
+// base and three times the previous delay.
+func NewDecorrelatedJitterBackoff(base time.Duration) *DecorrelatedJitterBackoff {
+	return &DecorrelatedJitterBackoff{base: base}
+}
+
+// NextDelay for DecorrelatedJitterBackoff returns min(maxDelay, random(base, previousDelay*3)).
+func (s *DecorrelatedJitterBackoff) NextDelay(state BackoffState) time.Duration {
+	upper := float64(max(state.PreviousDelay, s.base)) * 3
+	delay := durationFromFloat(float64(s.base) + rand.Float64()*(upper-float64(s.base)))
+	return capDelay(delay, state.MaxDelay)
+}
+
+// newLegacyBackoff builds the BackoffStrategy described by the interval, backoff rate and jitter fields.
+func newLegacyBackoff(interval time.Duration, backoffRate float64, jitter JitterStrategy) BackoffStrategy {
+	var strategy BackoffStrategy = NewExponentialBackoff(interval, backoffRate)
+	if jitter == JitterStrategyFull {
+		strategy = NewFullJitterBackoff(strategy)
+	}
+	return strategy
+}
+
+// capDelay limits delay to maxDelay, when maxDelay is set.
+func capDelay(delay, maxDelay time.Duration) time.Duration {
+	if maxDelay > 0 && delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+// durationFromFloat converts a float to a time.Duration, saturating instead of overflowing.
+func durationFromFloat(value float64) time.Duration {
+	switch {
+	case math.IsNaN(value) || value <= 0:
+		return 0
+	case value >= math.MaxInt64:
+		return time.Duration(math.MaxInt64)
+	default:
+		return time.Duration(value)
+	}
+}
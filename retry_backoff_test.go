@@ -0,0 +1,224 @@
+package fastshot
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBackoffStrategy_Deterministic(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy BackoffStrategy
+		expected []time.Duration
+	}{
+		{
+			name:     "Constant",
+			strategy: NewConstantBackoff(100 * time.Millisecond),
+			expected: []time.Duration{100 * time.Millisecond, 100 * time.Millisecond, 100 * time.Millisecond},
+		},
+		{
+			name:     "Linear",
+			strategy: NewLinearBackoff(100*time.Millisecond, 50*time.Millisecond),
+			expected: []time.Duration{100 * time.Millisecond, 150 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:     "Exponential",
+			strategy: NewExponentialBackoff(100*time.Millisecond, 2.0),
+			expected: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond},
+		},
+		{
+			name:     "Fibonacci",
+			strategy: NewFibonacciBackoff(100 * time.Millisecond),
+			expected: []time.Duration{
+				100 * time.Millisecond,
+				100 * time.Millisecond,
+				200 * time.Millisecond,
+				300 * time.Millisecond,
+				500 * time.Millisecond,
+				800 * time.Millisecond,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for attempt, want := range tt.expected {
+				// Act
+				got := tt.strategy.NextDelay(BackoffState{Attempt: uint(attempt)})
+
+				// Assert
+				if got != want {
+					t.Errorf("attempt %d got %v, want %v", attempt, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffStrategy_Jitter(t *testing.T) {
+	base := NewConstantBackoff(100 * time.Millisecond)
+
+	tests := []struct {
+		name     string
+		strategy BackoffStrategy
+		state    BackoffState
+		min      time.Duration
+		max      time.Duration
+	}{
+		{
+			name:     "Full jitter",
+			strategy: NewFullJitterBackoff(base),
+			min:      0,
+			max:      100 * time.Millisecond,
+		},
+		{
+			name:     "Full jitter applies max delay before randomizing",
+			strategy: NewFullJitterBackoff(base),
+			state:    BackoffState{MaxDelay: 10 * time.Millisecond},
+			min:      0,
+			max:      10 * time.Millisecond,
+		},
+		{
+			name:     "Equal jitter",
+			strategy: NewEqualJitterBackoff(base),
+			min:      50 * time.Millisecond,
+			max:      100 * time.Millisecond,
+		},
+		{
+			name:     "Decorrelated jitter on first retry",
+			strategy: NewDecorrelatedJitterBackoff(100 * time.Millisecond),
+			min:      100 * time.Millisecond,
+			max:      300 * time.Millisecond,
+		},
+		{
+			name:     "Decorrelated jitter grows from previous delay",
+			strategy: NewDecorrelatedJitterBackoff(100 * time.Millisecond),
+			state:    BackoffState{Attempt: 3, PreviousDelay: time.Second},
+			min:      100 * time.Millisecond,
+			max:      3 * time.Second,
+		},
+		{
+			name:     "Decorrelated jitter is capped by max delay",
+			strategy: NewDecorrelatedJitterBackoff(100 * time.Millisecond),
+			state:    BackoffState{Attempt: 3, PreviousDelay: time.Second, MaxDelay: 200 * time.Millisecond},
+			min:      100 * time.Millisecond,
+			max:      200 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 100 {
+				// Act
+				got := tt.strategy.NextDelay(tt.state)
+
+				// Assert
+				if got < tt.min || got > tt.max {
+					t.Fatalf("got %v, want within [%v, %v]", got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffStrategy_Saturates(t *testing.T) {
+	// Arrange
+	strategy := NewExponentialBackoff(time.Second, 10)
+
+	// Act
+	got := strategy.NextDelay(BackoffState{Attempt: 1000})
+
+	// Assert
+	if got != time.Duration(math.MaxInt64) {
+		t.Errorf("got %v, want %v", got, time.Duration(math.MaxInt64))
+	}
+}
+
+func TestRetryConfig_BackoffStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy BackoffStrategy
+		expected time.Duration
+	}{
+		{
+			name:     "Derived from the interval and backoff rate",
+			expected: 90 * time.Millisecond,
+		},
+		{
+			name:     "Strategy takes precedence",
+			strategy: NewConstantBackoff(time.Second),
+			expected: time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			config := newRequestConfigBase("", "").RetryConfig()
+			config.SetBackoffStrategy(tt.strategy)
+
+			// Act
+			config.SetInterval(10 * time.Millisecond)
+			config.SetBackoffRate(3)
+			config.SetJitterStrategy(JitterStrategyNone)
+			got := config.BackoffStrategy().NextDelay(BackoffState{Attempt: 2})
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRetryConfig_setBackoffPreset(t *testing.T) {
+	tests := []struct {
+		name        string
+		backoffRate float64
+		jitter      JitterStrategy
+		expected    time.Duration
+	}{
+		{
+			name:        "Constant",
+			backoffRate: 1,
+			jitter:      JitterStrategyNone,
+			expected:    10 * time.Millisecond,
+		},
+		{
+			name:        "Exponential",
+			backoffRate: 2,
+			jitter:      JitterStrategyNone,
+			expected:    40 * time.Millisecond,
+		},
+		{
+			name:        "Exponential with jitter",
+			backoffRate: 2,
+			jitter:      JitterStrategyFull,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			config := newRequestConfigBase("", "").RetryConfig()
+
+			// Act
+			config.setBackoffPreset(10*time.Millisecond, 3, tt.backoffRate, tt.jitter)
+
+			// Assert
+			if config.Interval() != 10*time.Millisecond || config.MaxAttempts() != 3 ||
+				config.BackoffRate() != tt.backoffRate || config.JitterStrategy() != tt.jitter {
+				t.Errorf("got interval %v, attempts %d, rate %v, jitter %v, want 10ms, 3, %v, %v",
+					config.Interval(), config.MaxAttempts(), config.BackoffRate(), config.JitterStrategy(), tt.backoffRate, tt.jitter)
+			}
+			got := config.BackoffStrategy().NextDelay(BackoffState{Attempt: 2})
+			if _, jittered := config.BackoffStrategy().(*FullJitterBackoff); jittered != (tt.jitter == JitterStrategyFull) {
+				t.Errorf("got jittered %v, want %v", jittered, tt.jitter == JitterStrategyFull)
+			}
+			if tt.jitter == JitterStrategyNone && got != tt.expected {
+				t.Errorf("delay got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
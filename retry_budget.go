@@ -0,0 +1,96 @@
+package fastshot
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/opus-domini/fast-shot/constant"
+)
+
+// ErrRetryBudgetExhausted is returned when a retry is skipped because the client retry budget is spent.
+var ErrRetryBudgetExhausted = errors.New(constant.ErrMsgRetryBudgetExhausted)
+
+// defaultRetryBudgetWindow is the window over which requests and retries are accounted.
+const defaultRetryBudgetWindow = 10 * time.Second
+
+type (
+	// RetryBudget caps retries to a share of recent requests plus a minimum number per second,
+	// so a degraded downstream service is not overwhelmed by retry amplification.
+	// It is safe for concurrent use by every request of a client.
+	RetryBudget struct {
+		mutex               sync.Mutex
+		ratio               float64
+		minRetriesPerSecond uint
+		buckets             []retryBudgetBucket
+		now                 func() time.Time
+	}
+
+	// retryBudgetBucket accounts requests and retries made within one second.
+	retryBudgetBucket struct {
+		second   int64
+		requests uint
+		retries  uint
+	}
+)
+
+// NewRetryBudget creates a RetryBudget that allows retries up to ratio (e.g. 0.2 for 20%) of the
+// requests made within the window, plus minRetriesPerSecond retries per second of the window.
+func NewRetryBudget(ratio float64, minRetriesPerSecond uint, window time.Duration) *RetryBudget {
+	seconds := int(math.Ceil(window.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return &RetryBudget{
+		ratio:               math.Max(ratio, 0),
+		minRetriesPerSecond: minRetriesPerSecond,
+		buckets:             make([]retryBudgetBucket, seconds),
+		now:                 time.Now,
+	}
+}
+
+// RecordRequest deposits a new request into the budget.
+func (b *RetryBudget) RecordRequest() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.bucket().requests++
+}
+
+// TryRetry withdraws a retry from the budget, reporting false when the budget is exhausted.
+func (b *RetryBudget) TryRetry() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	current := b.bucket()
+	var requests, retries uint
+	for _, bucket := range b.buckets {
+		if b.isLive(bucket, current.second) {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+
+	reserve := float64(b.minRetriesPerSecond) * float64(len(b.buckets))
+	if float64(requests)*b.ratio+reserve-float64(retries) < 1 {
+		return false
+	}
+
+	current.retries++
+	return true
+}
+
+// bucket returns the bucket for the current second, resetting it when it belongs to an old window.
+func (b *RetryBudget) bucket() *retryBudgetBucket {
+	second := b.now().Unix()
+	bucket := &b.buckets[int(second%int64(len(b.buckets)))]
+	if bucket.second != second {
+		*bucket = retryBudgetBucket{second: second}
+	}
+	return bucket
+}
+
+// isLive reports whether the bucket falls within the window ending at the given second.
+func (b *RetryBudget) isLive(bucket retryBudgetBucket, second int64) bool {
+	return second-bucket.second < int64(len(b.buckets))
+}
@@ -0,0 +1,116 @@
+package fastshot
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRetryBudget(t *testing.T) {
+	tests := []struct {
+		name            string
+		ratio           float64
+		minPerSecond    uint
+		requests        int
+		expectedRetries int
+	}{
+		{
+			name:            "Ratio of requests",
+			ratio:           0.2,
+			requests:        50,
+			expectedRetries: 10,
+		},
+		{
+			name:            "Minimum retries without traffic",
+			minPerSecond:    1,
+			requests:        0,
+			expectedRetries: 10,
+		},
+		{
+			name:            "Ratio plus minimum",
+			ratio:           0.1,
+			minPerSecond:    1,
+			requests:        100,
+			expectedRetries: 20,
+		},
+		{
+			name:            "No budget",
+			requests:        100,
+			expectedRetries: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			now := time.Unix(1_700_000_000, 0)
+			budget := NewRetryBudget(tt.ratio, tt.minPerSecond, 10*time.Second)
+			budget.now = func() time.Time { return now }
+			for range tt.requests {
+				budget.RecordRequest()
+			}
+
+			// Act
+			retries := 0
+			for budget.TryRetry() {
+				retries++
+			}
+
+			// Assert
+			if retries != tt.expectedRetries {
+				t.Errorf("retries got %d, want %d", retries, tt.expectedRetries)
+			}
+		})
+	}
+}
+
+func TestRetryBudget_WindowExpires(t *testing.T) {
+	// Arrange
+	now := time.Unix(1_700_000_000, 0)
+	budget := NewRetryBudget(0.5, 0, 2*time.Second)
+	budget.now = func() time.Time { return now }
+	for range 4 {
+		budget.RecordRequest()
+	}
+	for budget.TryRetry() {
+	}
+
+	// Act
+	now = now.Add(2 * time.Second)
+	allowed := budget.TryRetry()
+
+	// Assert
+	if allowed {
+		t.Error("retry allowed after the window expired, want denied")
+	}
+}
+
+func TestRetryBudget_Concurrent(t *testing.T) {
+	// Arrange
+	budget := NewRetryBudget(0.1, 0, time.Minute)
+	for range 1000 {
+		budget.RecordRequest()
+	}
+
+	// Act
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	retries := 0
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for budget.TryRetry() {
+				mutex.Lock()
+				retries++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	if retries != 100 {
+		t.Errorf("retries got %d, want 100", retries)
+	}
+}
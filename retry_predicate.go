@@ -0,0 +1,126 @@
+package fastshot
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/opus-domini/fast-shot/constant/method"
+)
+
+type (
+	// RetryAttempt describes the attempt evaluated by a RetryPredicate.
+	RetryAttempt struct {
+		// Request is the *http.Request sent on this attempt.
+		Request *http.Request
+		// Number is the 1-based number of this attempt.
+		Number uint
+		// MaxAttempts is the configured maximum number of attempts.
+		MaxAttempts uint
+		// Elapsed is the time since the first attempt started.
+		Elapsed time.Duration
+	}
+
+	// RetryPredicate decides whether an attempt should be retried. The response is nil when the
+	// attempt failed with a transport error, and err is nil when a response was received.
+	RetryPredicate func(response *Response, err error, attempt RetryAttempt) bool
+)
+
+// RetryOnAll returns a RetryPredicate that retries only when every given predicate agrees.
+func RetryOnAll(predicates ...RetryPredicate) RetryPredicate {
+	return func(response *Response, err error, attempt RetryAttempt) bool {
+		for _, predicate := range predicates {
+			if !predicate(response, err, attempt) {
+				return false
+			}
+		}
+		return len(predicates) > 0
+	}
+}
+
+// RetryOnAny returns a RetryPredicate that retries when at least one of the given predicates agrees.
+func RetryOnAny(predicates ...RetryPredicate) RetryPredicate {
+	return func(response *Response, err error, attempt RetryAttempt) bool {
+		for _, predicate := range predicates {
+			if predicate(response, err, attempt) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// RetryOnIdempotentMethods allows retries only for idempotent HTTP methods as defined by RFC 9110.
+// It is meant to be combined with other predicates through RetryOnAll.
+func RetryOnIdempotentMethods(_ *Response, _ error, attempt RetryAttempt) bool {
+	return attempt.Request != nil && isIdempotentMethod(method.Parse(attempt.Request.Method))
+}
+
+// isIdempotentMethod reports whether the method is idempotent as defined by RFC 9110.
+func isIdempotentMethod(m method.Type) bool {
+	switch m {
+	case method.GET, method.HEAD, method.OPTIONS, method.TRACE, method.PUT, method.DELETE:
+		return true
+	default:
+		return false
+	}
+}
+
+// RetryOnNetworkErrors retries transport errors that are likely to be transient, such as
+// timeouts, connection resets and refusals, and DNS failures. TLS verification errors are never retried.
+func RetryOnNetworkErrors(_ *Response, err error, _ RetryAttempt) bool {
+	return isTemporaryNetworkError(err)
+}
+
+// RetryOn5xx retries responses with a 5xx server error status.
+func RetryOn5xx(response *Response, _ error, _ RetryAttempt) bool {
+	return response != nil && response.Status().Is5xxServerError()
+}
+
+// RetryOnTooManyRequests retries responses with a 429 Too Many Requests status.
+func RetryOnTooManyRequests(response *Response, _ error, _ RetryAttempt) bool {
+	return response != nil && response.Status().Code() == http.StatusTooManyRequests
+}
+
+// isTemporaryNetworkError reports whether err is a transport error worth retrying.
+func isTemporaryNetworkError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// Certificate and handshake failures will not fix themselves
+	var certificateErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCertificateErr x509.CertificateInvalidError
+	var recordHeaderErr tls.RecordHeaderError
+	if errors.As(err, &certificateErr) ||
+		errors.As(err, &unknownAuthorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidCertificateErr) ||
+		errors.As(err, &recordHeaderErr) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return !dnsErr.IsNotFound
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
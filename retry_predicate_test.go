@@ -0,0 +1,151 @@
+package fastshot
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+)
+
+func TestRetryPredicates(t *testing.T) {
+	get, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	post, _ := http.NewRequest(http.MethodPost, "https://example.com", nil)
+	response := func(code int) *Response {
+		return newResponse(&http.Response{StatusCode: code, Body: http.NoBody})
+	}
+
+	tests := []struct {
+		name      string
+		predicate RetryPredicate
+		response  *Response
+		err       error
+		attempt   RetryAttempt
+		expected  bool
+	}{
+		{
+			name:      "Idempotent method is allowed",
+			predicate: RetryOnIdempotentMethods,
+			attempt:   RetryAttempt{Request: get},
+			expected:  true,
+		},
+		{
+			name:      "Non-idempotent method is rejected",
+			predicate: RetryOnIdempotentMethods,
+			attempt:   RetryAttempt{Request: post},
+			expected:  false,
+		},
+		{
+			name:      "5xx response is retried",
+			predicate: RetryOn5xx,
+			response:  response(http.StatusBadGateway),
+			expected:  true,
+		},
+		{
+			name:      "4xx response is not retried by 5xx predicate",
+			predicate: RetryOn5xx,
+			response:  response(http.StatusBadRequest),
+			expected:  false,
+		},
+		{
+			name:      "Transport error is not retried by 5xx predicate",
+			predicate: RetryOn5xx,
+			err:       syscall.ECONNRESET,
+			expected:  false,
+		},
+		{
+			name:      "429 response is retried",
+			predicate: RetryOnTooManyRequests,
+			response:  response(http.StatusTooManyRequests),
+			expected:  true,
+		},
+		{
+			name:      "Connection reset is retried",
+			predicate: RetryOnNetworkErrors,
+			err:       &url.Error{Op: "Get", URL: "https://example.com", Err: syscall.ECONNRESET},
+			expected:  true,
+		},
+		{
+			name:      "Response is not retried by network predicate",
+			predicate: RetryOnNetworkErrors,
+			response:  response(http.StatusServiceUnavailable),
+			expected:  false,
+		},
+		{
+			name:      "All requires every predicate",
+			predicate: RetryOnAll(RetryOnIdempotentMethods, RetryOn5xx),
+			response:  response(http.StatusInternalServerError),
+			attempt:   RetryAttempt{Request: post},
+			expected:  false,
+		},
+		{
+			name:      "All without predicates never retries",
+			predicate: RetryOnAll(),
+			response:  response(http.StatusInternalServerError),
+			expected:  false,
+		},
+		{
+			name:      "Any accepts a single match",
+			predicate: RetryOnAny(RetryOnTooManyRequests, RetryOn5xx),
+			response:  response(http.StatusServiceUnavailable),
+			expected:  true,
+		},
+		{
+			name: "Composed predicate retries idempotent network errors",
+			predicate: RetryOnAll(
+				RetryOnIdempotentMethods,
+				RetryOnAny(RetryOnNetworkErrors, RetryOn5xx),
+			),
+			err:      &url.Error{Op: "Get", URL: "https://example.com", Err: syscall.ECONNREFUSED},
+			attempt:  RetryAttempt{Request: get},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := tt.predicate(tt.response, tt.err, tt.attempt)
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsTemporaryNetworkError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "Nil error", err: nil, expected: false},
+		{name: "Connection reset", err: syscall.ECONNRESET, expected: true},
+		{name: "Connection refused", err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, expected: true},
+		{name: "Unexpected EOF", err: fmt.Errorf("read: %w", io.ErrUnexpectedEOF), expected: true},
+		{name: "Timeout", err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}, expected: true},
+		{name: "Temporary DNS failure", err: &net.DNSError{Err: "server misbehaving", IsTemporary: true}, expected: true},
+		{name: "Unknown host", err: &net.DNSError{Err: "no such host", IsNotFound: true}, expected: false},
+		{name: "Deadline exceeded", err: &url.Error{Op: "Get", Err: context.DeadlineExceeded}, expected: true},
+		{name: "Unknown authority", err: &url.Error{Op: "Get", Err: x509.UnknownAuthorityError{}}, expected: false},
+		{name: "Certificate verification", err: &tls.CertificateVerificationError{Err: errors.New("bad cert")}, expected: false},
+		{name: "Generic error", err: errors.New("boom"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act & Assert
+			if got := isTemporaryNetworkError(tt.err); got != tt.expected {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -1,20 +1,71 @@
 package fastshot
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"net/url"
-	"sync/atomic"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/opus-domini/fast-shot/constant"
+)
+
+// Compile-time checks that BalancedBaseURL implements BaseURLSelector, BaseURLObserver and HashKeyProvider.
+var (
+	_ BaseURLSelector = (*BalancedBaseURL)(nil)
+	_ BaseURLObserver = (*BalancedBaseURL)(nil)
+	_ HashKeyProvider = (*BalancedBaseURL)(nil)
 )
 
 type (
+	// Endpoint describes a load-balanced backend and its relative share of traffic.
+	Endpoint struct {
+		// BaseURL is the base URL of the backend.
+		BaseURL string
+		// Weight is the relative share of traffic sent to the backend. Zero is treated as 1.
+		Weight uint
+		// Zone is the locality of the backend, such as an availability zone. Optional.
+		Zone string
+		// Priority orders the backends in groups, lowest first. Traffic goes to the group with
+		// the lowest priority that has enough available backends. Defaults to 0.
+		Priority uint
+	}
+
+	// BaseURLCriteria narrows the base URLs a BaseURLSelector may return for an attempt.
+	BaseURLCriteria struct {
+		// Exclude lists base URLs that should be skipped, such as backends that already failed.
+		Exclude []*url.URL
+		// HashKey is the key consistent hashing strategies use to pin the request to a base URL.
+		// It is empty when the request has no key.
+		HashKey string
+	}
+
 	// DefaultBaseURL implements ConfigBaseURL interface and provides a single base URL.
 	DefaultBaseURL struct {
 		baseURL *url.URL
 	}
 
 	// BalancedBaseURL implements ConfigBaseURL interface and provides load balancing.
+	// The base URL of each attempt is chosen by a LoadBalancingStrategy, which is told when
+	// requests to a base URL start and finish. Base URLs marked unhealthy, or ejected by the
+	// outlier detector, are taken out of rotation until they recover. Only the highest-ranked
+	// priority group with enough available base URLs takes traffic. The set of base URLs can
+	// be updated at any time through SetEndpoints.
 	BalancedBaseURL struct {
-		baseURLs       []*url.URL
-		currentBaseURL uint32
+		strategy  LoadBalancingStrategy
+		outliers  *OutlierDetector
+		hashKey   HashKeyFunc
+		groups    PriorityGroupsConfig
+		slowStart SlowStartConfig
+		now       func() time.Time
+		mu        sync.RWMutex
+		backends  []Backend
+		unhealthy map[*url.URL]bool
+		inflight  map[*url.URL]int
+		drained   map[*url.URL][]chan struct{}
+		joined    map[*url.URL]time.Time
 	}
 )
 
@@ -23,10 +74,190 @@ func (c *DefaultBaseURL) BaseURL() *url.URL {
 	return c.baseURL
 }
 
-// BaseURL for BalancedBaseURL returns the next base URL in the list.
+// BaseURL for BalancedBaseURL returns the next base URL chosen by the strategy.
 func (c *BalancedBaseURL) BaseURL() *url.URL {
-	index := atomic.AddUint32(&c.currentBaseURL, 1) - 1
-	return c.baseURLs[index%uint32(len(c.baseURLs))]
+	return c.SelectBaseURL(BaseURLCriteria{})
+}
+
+// SelectBaseURL for BalancedBaseURL returns the base URL chosen by the strategy among the available
+// ones that are not excluded, in the highest-ranked priority group with enough of them. When no base
+// URL is available, every base URL is considered, and when every remaining base URL is excluded, the
+// exclusions are ignored. It returns nil when the balancer has no base URL at all.
+func (c *BalancedBaseURL) SelectBaseURL(criteria BaseURLCriteria) *url.URL {
+	backends := c.snapshot()
+	if len(backends) == 0 {
+		return nil
+	}
+
+	candidates := c.availableBackends(backends)
+	if len(criteria.Exclude) > 0 {
+		included := make([]Backend, 0, len(candidates))
+		for _, backend := range candidates {
+			if !criteria.excludes(backend.URL) {
+				included = append(included, backend)
+			}
+		}
+		if len(included) > 0 {
+			candidates = included
+		}
+	}
+	candidates = c.rampWeights(c.priorityGroup(backends, candidates))
+	return candidates[c.strategy.Select(candidates, criteria)].URL
+}
+
+// SetHealthy for BalancedBaseURL takes the base URL out of rotation, or puts it back. A base URL
+// put back starts over its slow start, if enabled.
+func (c *BalancedBaseURL) SetHealthy(baseURL *url.URL, healthy bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if healthy {
+		if c.unhealthy[baseURL] {
+			delete(c.unhealthy, baseURL)
+			c.markJoined(baseURL)
+		}
+		return
+	}
+	if c.unhealthy == nil {
+		c.unhealthy = make(map[*url.URL]bool)
+	}
+	c.unhealthy[baseURL] = true
+}
+
+// IsHealthy for BalancedBaseURL reports whether the base URL is in rotation.
+func (c *BalancedBaseURL) IsHealthy(baseURL *url.URL) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !c.unhealthy[baseURL]
+}
+
+// Backends for BalancedBaseURL returns the backends, healthy or not.
+func (c *BalancedBaseURL) Backends() []Backend {
+	return slices.Clone(c.snapshot())
+}
+
+// Balanced for BalancedBaseURL reports that requests are spread across equivalent base URLs.
+func (c *BalancedBaseURL) Balanced() bool {
+	return true
+}
+
+// HashKeyFunc for BalancedBaseURL returns the function that derives the hash key of each request, if any.
+func (c *BalancedBaseURL) HashKeyFunc() HashKeyFunc {
+	return c.hashKey
+}
+
+// SetHashKeyFunc for BalancedBaseURL sets the function that derives the hash key of each request.
+func (c *BalancedBaseURL) SetHashKeyFunc(hashKey HashKeyFunc) {
+	c.hashKey = hashKey
+}
+
+// OutlierDetector for BalancedBaseURL returns the outlier detector, if any.
+func (c *BalancedBaseURL) OutlierDetector() *OutlierDetector {
+	return c.outliers
+}
+
+// SetOutlierDetector for BalancedBaseURL sets the outlier detector fed by the request outcomes.
+func (c *BalancedBaseURL) SetOutlierDetector(detector *OutlierDetector) {
+	c.outliers = detector
+}
+
+// snapshot returns the current backends. The slice is replaced, never modified, on updates.
+func (c *BalancedBaseURL) snapshot() []Backend {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.backends
+}
+
+// availableBackends returns the backends that are healthy and not ejected, or every backend when
+// none is available.
+func (c *BalancedBaseURL) availableBackends(backends []Backend) []Backend {
+	available := c.healthyBackends(backends)
+	if c.outliers != nil {
+		available = c.outliers.filter(available)
+	}
+	if len(available) == 0 {
+		return backends
+	}
+	return available
+}
+
+// healthyBackends returns the backends that are not marked unhealthy.
+func (c *BalancedBaseURL) healthyBackends(backends []Backend) []Backend {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.unhealthy) == 0 {
+		return backends
+	}
+
+	healthy := make([]Backend, 0, len(backends))
+	for _, backend := range backends {
+		if !c.unhealthy[backend.URL] {
+			healthy = append(healthy, backend)
+		}
+	}
+	return healthy
+}
+
+// Strategy for BalancedBaseURL returns the load balancing strategy.
+func (c *BalancedBaseURL) Strategy() LoadBalancingStrategy {
+	return c.strategy
+}
+
+// SetStrategy for BalancedBaseURL sets the load balancing strategy.
+func (c *BalancedBaseURL) SetStrategy(strategy LoadBalancingStrategy) {
+	c.strategy = strategy
+}
+
+// RequestStarted for BalancedBaseURL tells the strategy that a request to the base URL started,
+// and returns the function that tells it the request finished.
+func (c *BalancedBaseURL) RequestStarted(baseURL *url.URL) func() {
+	c.mu.Lock()
+	if c.inflight == nil {
+		c.inflight = make(map[*url.URL]int)
+	}
+	c.inflight[baseURL]++
+	c.mu.Unlock()
+
+	c.strategy.Start(baseURL)
+	return func() {
+		c.strategy.Finish(baseURL)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.inflight[baseURL]--
+		if c.inflight[baseURL] > 0 {
+			return
+		}
+		delete(c.inflight, baseURL)
+		for _, done := range c.drained[baseURL] {
+			close(done)
+		}
+		delete(c.drained, baseURL)
+	}
+}
+
+// RecordOutcome for BalancedBaseURL feeds the outcome of a request to the outlier detector, if any.
+// Outcomes of base URLs that are no longer members are ignored.
+func (c *BalancedBaseURL) RecordOutcome(baseURL *url.URL, response *http.Response, err error) {
+	if c.outliers == nil {
+		return
+	}
+	backends := c.snapshot()
+	for _, backend := range backends {
+		if backend.URL == baseURL {
+			c.outliers.Record(baseURL, len(backends), response, err)
+			return
+		}
+	}
+}
+
+// excludes reports whether the base URL matches one of the excluded base URLs.
+func (c BaseURLCriteria) excludes(baseURL *url.URL) bool {
+	for _, excluded := range c.Exclude {
+		if excluded != nil && baseURL != nil && excluded.String() == baseURL.String() {
+			return true
+		}
+	}
+	return false
 }
 
 // newDefaultBaseURL initializes a new DefaultBaseURL with a given base URL.
@@ -38,7 +269,69 @@ func newDefaultBaseURL(baseURL *url.URL) *DefaultBaseURL {
 
 // newBalancedBaseURL initializes a new BalancedBaseURL with a given base URLs.
 func newBalancedBaseURL(baseURLs []*url.URL) *BalancedBaseURL {
+	return newWeightedBalancedBaseURL(baseURLs, make([]uint, len(baseURLs)))
+}
+
+// newWeightedBalancedBaseURL initializes a new BalancedBaseURL with a given base URLs and their
+// weights, balanced with smooth weighted round-robin.
+func newWeightedBalancedBaseURL(baseURLs []*url.URL, weights []uint) *BalancedBaseURL {
+	backends := make([]Backend, len(baseURLs))
+	for index, baseURL := range baseURLs {
+		backends[index] = Backend{URL: baseURL, Weight: float64(max(weights[index], 1))}
+	}
+	return newBackendsBalancedBaseURL(backends)
+}
+
+// newBackendsBalancedBaseURL initializes a new BalancedBaseURL with a given backends, balanced with
+// smooth weighted round-robin.
+func newBackendsBalancedBaseURL(backends []Backend) *BalancedBaseURL {
 	return &BalancedBaseURL{
-		baseURLs: baseURLs,
+		backends: backends,
+		strategy: NewWeightedRoundRobinStrategy(),
+		now:      time.Now,
+	}
+}
+
+// parseEndpoints parses the endpoints into backends, rejecting the whole list when it is empty or
+// when one of them is invalid, such as a base URL without a scheme or host. Repeated base URLs
+// are listed once, with the attributes of their first occurrence.
+func parseEndpoints(endpoints []Endpoint) ([]Backend, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New(constant.ErrMsgEmptyBaseURL)
+	}
+
+	backends := make([]Backend, 0, len(endpoints))
+	seen := make(map[string]bool, len(endpoints))
+	for index, endpoint := range endpoints {
+		parsedURL, err := parseBaseURL(endpoint.BaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("base URL %d: %w", index, err)
+		}
+		if seen[parsedURL.String()] {
+			continue
+		}
+		seen[parsedURL.String()] = true
+		backends = append(backends, Backend{
+			URL:      parsedURL,
+			Weight:   float64(max(endpoint.Weight, 1)),
+			Priority: endpoint.Priority,
+			Zone:     endpoint.Zone,
+		})
+	}
+	return backends, nil
+}
+
+// parseBaseURL parses a base URL, which must be absolute with a scheme and host.
+func parseBaseURL(baseURL string) (*url.URL, error) {
+	if baseURL == "" {
+		return nil, errors.New(constant.ErrMsgEmptyBaseURL)
+	}
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, errors.Join(errors.New(constant.ErrMsgParseURL), err)
+	}
+	if parsedURL.Scheme == "" || parsedURL.Host == "" {
+		return nil, fmt.Errorf("%s: %q", constant.ErrMsgRelativeBaseURL, baseURL)
 	}
+	return parsedURL, nil
 }
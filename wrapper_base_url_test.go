@@ -80,3 +80,218 @@ func TestBalancedBaseURL_Concurrent(t *testing.T) {
 		}
 	}
 }
+
+func TestBalancedBaseURL_SelectBaseURL(t *testing.T) {
+	// Arrange
+	u1, _ := url.Parse("https://a.com")
+	u2, _ := url.Parse("https://b.com")
+	u3, _ := url.Parse("https://c.com")
+	excludedU1, _ := url.Parse("https://a.com")
+
+	tests := []struct {
+		name     string
+		criteria BaseURLCriteria
+		expected []*url.URL
+	}{
+		{
+			name:     "No exclusions keeps round robin",
+			criteria: BaseURLCriteria{},
+			expected: []*url.URL{u1, u2, u3, u1},
+		},
+		{
+			name:     "Excluded base URL is skipped",
+			criteria: BaseURLCriteria{Exclude: []*url.URL{excludedU1}},
+			expected: []*url.URL{u2, u3, u2, u3},
+		},
+		{
+			name:     "All excluded falls back to round robin",
+			criteria: BaseURLCriteria{Exclude: []*url.URL{u1, u2, u3}},
+			expected: []*url.URL{u1, u2, u3, u1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := newBalancedBaseURL([]*url.URL{u1, u2, u3})
+
+			for i, want := range tt.expected {
+				// Act
+				got := base.SelectBaseURL(tt.criteria)
+
+				// Assert
+				if got != want {
+					t.Errorf("selection %d got %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBalancedBaseURL_WeightedRoundRobin(t *testing.T) {
+	// Arrange
+	u1, _ := url.Parse("https://a.com")
+	u2, _ := url.Parse("https://b.com")
+	u3, _ := url.Parse("https://c.com")
+	excludedU1, _ := url.Parse("https://a.com")
+
+	tests := []struct {
+		name     string
+		weights  []uint
+		criteria BaseURLCriteria
+		expected []*url.URL
+	}{
+		{
+			name:     "Smooth weighted sequence",
+			weights:  []uint{5, 1, 1},
+			expected: []*url.URL{u1, u1, u2, u1, u3, u1, u1, u1, u1, u2},
+		},
+		{
+			name:     "Zero weight is treated as 1",
+			weights:  []uint{2, 0, 1},
+			expected: []*url.URL{u1, u2, u3, u1},
+		},
+		{
+			name:     "Equal weights keep round robin",
+			weights:  []uint{3, 3, 3},
+			expected: []*url.URL{u1, u2, u3, u1},
+		},
+		{
+			name:     "Excluded base URL is skipped",
+			weights:  []uint{5, 1, 1},
+			criteria: BaseURLCriteria{Exclude: []*url.URL{excludedU1}},
+			expected: []*url.URL{u2, u3, u2, u3},
+		},
+		{
+			name:     "All excluded falls back to weighted rotation",
+			weights:  []uint{5, 1, 1},
+			criteria: BaseURLCriteria{Exclude: []*url.URL{u1, u2, u3}},
+			expected: []*url.URL{u1, u1, u2, u1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := newWeightedBalancedBaseURL([]*url.URL{u1, u2, u3}, tt.weights)
+
+			for i, want := range tt.expected {
+				// Act
+				got := base.SelectBaseURL(tt.criteria)
+
+				// Assert
+				if got != want {
+					t.Errorf("selection %d got %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBalancedBaseURL_WeightedShare(t *testing.T) {
+	// Arrange
+	stable, _ := url.Parse("https://stable.com")
+	canary, _ := url.Parse("https://canary.com")
+	base := newWeightedBalancedBaseURL([]*url.URL{stable, canary}, []uint{95, 5})
+
+	// Act
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	counts := make(map[*url.URL]int)
+	for range 200 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u := base.BaseURL()
+			mu.Lock()
+			counts[u]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	if got := counts[stable]; got != 190 {
+		t.Errorf("stable count got %d, want 190", got)
+	}
+	if got := counts[canary]; got != 10 {
+		t.Errorf("canary count got %d, want 10", got)
+	}
+}
+
+func TestBalancedBaseURL_Health(t *testing.T) {
+	// Arrange
+	u1, _ := url.Parse("https://a.com")
+	u2, _ := url.Parse("https://b.com")
+	u3, _ := url.Parse("https://c.com")
+
+	tests := []struct {
+		name      string
+		unhealthy []*url.URL
+		criteria  BaseURLCriteria
+		expected  []*url.URL
+	}{
+		{
+			name:      "Unhealthy base URL is out of rotation",
+			unhealthy: []*url.URL{u2},
+			expected:  []*url.URL{u1, u3, u1, u3},
+		},
+		{
+			name:      "All unhealthy keeps every base URL",
+			unhealthy: []*url.URL{u1, u2, u3},
+			expected:  []*url.URL{u1, u2, u3, u1},
+		},
+		{
+			name:      "Exclusions apply to healthy base URLs",
+			unhealthy: []*url.URL{u2},
+			criteria:  BaseURLCriteria{Exclude: []*url.URL{u1}},
+			expected:  []*url.URL{u3, u3},
+		},
+		{
+			name:      "Exclusions are ignored when no healthy base URL is left",
+			unhealthy: []*url.URL{u2},
+			criteria:  BaseURLCriteria{Exclude: []*url.URL{u1, u3}},
+			expected:  []*url.URL{u1, u3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := newBalancedBaseURL([]*url.URL{u1, u2, u3})
+			for _, u := range tt.unhealthy {
+				base.SetHealthy(u, false)
+			}
+
+			for i, want := range tt.expected {
+				// Act
+				got := base.SelectBaseURL(tt.criteria)
+
+				// Assert
+				if got != want {
+					t.Errorf("selection %d got %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBalancedBaseURL_SetHealthy(t *testing.T) {
+	// Arrange
+	u1, _ := url.Parse("https://a.com")
+	u2, _ := url.Parse("https://b.com")
+	base := newBalancedBaseURL([]*url.URL{u1, u2})
+
+	// Act
+	base.SetHealthy(u1, false)
+	unhealthy := base.IsHealthy(u1)
+	base.SetHealthy(u1, true)
+
+	// Assert
+	if unhealthy {
+		t.Error("got healthy, want unhealthy")
+	}
+	if !base.IsHealthy(u1) {
+		t.Error("got unhealthy, want healthy after recovery")
+	}
+	if got := base.SelectBaseURL(BaseURLCriteria{}); got != u1 {
+		t.Errorf("got %v, want %v", got, u1)
+	}
+}